@@ -8,20 +8,27 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/sarff/iSlogger"
 
+	"github.com/sarff/FundsPulse/internal/alerts"
+	"github.com/sarff/FundsPulse/internal/api"
+	"github.com/sarff/FundsPulse/internal/backup"
 	"github.com/sarff/FundsPulse/internal/checker"
 	"github.com/sarff/FundsPulse/internal/config"
+	"github.com/sarff/FundsPulse/internal/fx"
 	"github.com/sarff/FundsPulse/internal/history"
 	"github.com/sarff/FundsPulse/internal/notify"
 	"github.com/sarff/FundsPulse/internal/service"
+	"github.com/sarff/FundsPulse/internal/webserver"
 )
 
 func main() {
 	configPath := flag.String("config", "config.yaml", "Path to configuration file")
 	runOnce := flag.Bool("run-once", false, "Run balance check immediately and exit")
+	restoreArchive := flag.String("restore", "", "Restore history from the named backup archive and exit")
 	flag.Parse()
 
 	logger, err := iSlogger.New(iSlogger.DefaultConfig().WithAppName("FundsPulse"))
@@ -42,16 +49,83 @@ func main() {
 		os.Exit(1)
 	}
 
-	notifier, err := notify.NewTelegram(cfg.Telegram.Token)
+	notifier, err := notify.Build(cfg.Notifiers)
 	if err != nil {
-		logger.Error("init telegram", "error", err)
+		logger.Error("init notifiers", "error", err)
 		os.Exit(1)
 	}
 
-	client := service.NewClient()
-	historyManager := history.NewManager(cfg.DaysForAverage)
+	fxRates, err := fx.Build(cfg.FX)
+	if err != nil {
+		logger.Error("init fx rates", "error", err)
+		os.Exit(1)
+	}
 
-	balanceChecker, err := checker.New(cfg, client, historyManager, notifier, logger)
+	client := service.NewClient(cfg.Concurrency.PerHostRateLimit, cfg.Concurrency.PerHostBurst)
+	fetchers := service.NewRegistry(client, fxRates, cfg.FX.ReportingCurrency)
+
+	historyStore, err := history.BuildStore(cfg.HistoryStore)
+	if err != nil {
+		logger.Error("init history store", "error", err)
+		os.Exit(1)
+	}
+	historyManager := history.NewManager(historyStore, cfg.DaysForAverage, cfg.Anomaly.Alpha, cfg.Anomaly.Threshold)
+
+	var store *webserver.Store
+	if cfg.Webserver.Enabled {
+		store = webserver.NewStore()
+	}
+
+	var backupManager *backup.Manager
+	if cfg.Backup.Enabled {
+		configSnapshot, err := cfg.Redact()
+		if err != nil {
+			logger.Error("redact config for backup", "error", err)
+			os.Exit(1)
+		}
+		backupManager, err = backup.New(cfg.Backup, cfg.HistoryDir, configSnapshot, logger)
+		if err != nil {
+			logger.Error("init backup manager", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	if *restoreArchive != "" {
+		if backupManager == nil {
+			logger.Error("restore requested but backup is not enabled in config")
+			os.Exit(1)
+		}
+		if err := backupManager.Restore(context.Background(), *restoreArchive); err != nil {
+			logger.Error("restore backup", "archive", *restoreArchive, "error", err)
+			os.Exit(1)
+		}
+		logger.Info("Restore complete", "archive", *restoreArchive)
+		return
+	}
+
+	var alertEngine *alerts.Engine
+	if cfg.Alerts.Enabled {
+		var sinks []alerts.Sink
+		if cfg.Alerts.Webhook != nil {
+			webhookSink, err := alerts.NewWebhookSink(cfg.Alerts.Webhook.URL, cfg.Alerts.Webhook.Secret, cfg.Alerts.Webhook.SignatureHdr, cfg.Alerts.Webhook.MaxRetries)
+			if err != nil {
+				logger.Error("init alert webhook sink", "error", err)
+				os.Exit(1)
+			}
+			sinks = append(sinks, webhookSink)
+		}
+		if cfg.Alerts.UseNotifiers {
+			sinks = append(sinks, alerts.NewNotifierSink(notifier))
+		}
+
+		persistDir := ""
+		if cfg.Alerts.PersistState {
+			persistDir = cfg.HistoryDir
+		}
+		alertEngine = alerts.NewEngine(sinks, persistDir, logger)
+	}
+
+	balanceChecker, err := checker.New(cfg, fetchers, historyManager, notifier, logger, store, backupManager, alertEngine)
 	if err != nil {
 		logger.Error("init checker", "error", err)
 		os.Exit(1)
@@ -68,6 +142,24 @@ func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
+	if cfg.Webserver.Enabled {
+		dashboard := webserver.NewServer(cfg.Webserver.Addr, cfg, store, historyManager, logger)
+		go func() {
+			if err := dashboard.Start(ctx); err != nil {
+				logger.Error("dashboard stopped with error", "error", err)
+			}
+		}()
+	}
+
+	if cfg.API.Enabled {
+		apiServer := api.NewServer(cfg.API.Addr, cfg, historyManager, balanceChecker, time.Duration(cfg.API.CacheTTLSeconds)*time.Second, logger)
+		go func() {
+			if err := apiServer.Start(ctx); err != nil {
+				logger.Error("api server stopped with error", "error", err)
+			}
+		}()
+	}
+
 	if err := balanceChecker.Start(ctx); err != nil && !errors.Is(err, context.Canceled) {
 		logger.Error("scheduler stopped with error", "error", err)
 		os.Exit(1)
@@ -0,0 +1,32 @@
+// Package alerts evaluates per-service threshold rules against fresh balance
+// checks and dispatches firing/resolved notifications to configurable sinks.
+package alerts
+
+import (
+	"context"
+	"time"
+)
+
+// Rule types recognised in ServiceConfig.Alerts.
+const (
+	RuleBalanceBelow        = "balance_below"
+	RuleSpendAboveDailyAvgX = "spend_above_daily_avg_x"
+	RuleCurrencyChange      = "currency_change"
+	RuleFetchErrorStreak    = "fetch_error_streak"
+)
+
+// Event describes one rule transition, ready for a Sink to deliver.
+type Event struct {
+	Service   string
+	Rule      string
+	State     string // "firing" or "resolved"
+	Message   string
+	Value     float64
+	Threshold float64
+	Timestamp time.Time
+}
+
+// Sink delivers an alert Event to an external system.
+type Sink interface {
+	Deliver(ctx context.Context, event Event) error
+}
@@ -0,0 +1,271 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sarff/iSlogger"
+
+	"github.com/sarff/FundsPulse/internal/config"
+	"github.com/sarff/FundsPulse/internal/history"
+	"github.com/sarff/FundsPulse/internal/service"
+)
+
+// Engine evaluates configured alert rules against fresh balance checks and
+// fans firing/resolved transitions out to its sinks. State is deduplicated
+// in memory per state key (one per service, or one per entry for services
+// with multiple balance entries), and optionally persisted next to the
+// history JSON.
+type Engine struct {
+	sinks      []Sink
+	persistDir string
+	logger     *iSlogger.Logger
+
+	mu     sync.Mutex
+	states map[string]*serviceState
+}
+
+// serviceState tracks rule firing state for one service so repeat
+// evaluations only notify on transitions.
+type serviceState struct {
+	RuleFiring   map[string]bool `json:"rule_firing"`
+	LastCurrency string          `json:"last_currency"`
+	ErrorStreak  int             `json:"error_streak"`
+}
+
+func newServiceState() *serviceState {
+	return &serviceState{RuleFiring: make(map[string]bool)}
+}
+
+// NewEngine builds an alert engine dispatching to sinks. When persistDir is
+// non-empty, per-state-key state is persisted as "<key>.alerts.json" under
+// it so dedup survives restarts.
+func NewEngine(sinks []Sink, persistDir string, logger *iSlogger.Logger) *Engine {
+	return &Engine{
+		sinks:      sinks,
+		persistDir: persistDir,
+		logger:     logger,
+		states:     make(map[string]*serviceState),
+	}
+}
+
+// Evaluate checks every configured rule for svc against the freshly fetched
+// entry and its history stats, notifying sinks on firing/resolved transitions.
+// stateKey scopes the dedup/firing state Evaluate reads and writes; callers
+// with multiple entries per service must pass a distinct stateKey per entry
+// (see checker.alertStateKey) so one entry's currency change or threshold
+// crossing doesn't mask or spuriously resolve another's.
+func (e *Engine) Evaluate(ctx context.Context, stateKey string, svc config.ServiceConfig, entry service.BalanceEntry, stats history.Result) error {
+	if len(svc.Alerts) == 0 {
+		return nil
+	}
+
+	e.mu.Lock()
+	state := e.stateFor(stateKey)
+	state.ErrorStreak = 0
+	var svcState *serviceState
+	if stateKey != svc.Name {
+		// RecordFetchError keeps the streak under svc.Name, so a successful
+		// fetch ends it there as well.
+		svcState = e.stateFor(svc.Name)
+		svcState.ErrorStreak = 0
+	}
+	e.mu.Unlock()
+
+	var errs []error
+	for _, rule := range svc.Alerts {
+		firing, value, message := evaluateRule(rule, entry, stats, state)
+		if err := e.transition(ctx, svc.Name, rule.Type, rule.Threshold, firing, value, message, state); err != nil {
+			errs = append(errs, err)
+		}
+		if svcState != nil && rule.Type == RuleFetchErrorStreak {
+			if err := e.transition(ctx, svc.Name, rule.Type, rule.Threshold, false, 0, message, svcState); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if svcState != nil {
+		e.persist(svc.Name, svcState)
+	}
+
+	state.LastCurrency = entry.Currency
+
+	e.persist(stateKey, state)
+	return errors.Join(errs...)
+}
+
+// RecordFetchError increments the consecutive-error streak for serviceName
+// and evaluates any configured fetch_error_streak rule against it. A fetch
+// error aborts the whole service call before any entry is parsed, so unlike
+// Evaluate this has no per-entry stateKey: the streak is always keyed by
+// serviceName alone.
+func (e *Engine) RecordFetchError(ctx context.Context, serviceName string, rules []config.AlertRuleConfig, fetchErr error) error {
+	e.mu.Lock()
+	state := e.stateFor(serviceName)
+	state.ErrorStreak++
+	streak := state.ErrorStreak
+	e.mu.Unlock()
+
+	var errs []error
+	for _, rule := range rules {
+		if rule.Type != RuleFetchErrorStreak {
+			continue
+		}
+		firing := float64(streak) >= rule.Threshold
+		message := fmt.Sprintf("%d consecutive fetch errors (last: %v)", streak, fetchErr)
+		if err := e.transition(ctx, serviceName, rule.Type, rule.Threshold, firing, float64(streak), message, state); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	e.persist(serviceName, state)
+	return errors.Join(errs...)
+}
+
+func evaluateRule(rule config.AlertRuleConfig, entry service.BalanceEntry, stats history.Result, state *serviceState) (firing bool, value float64, message string) {
+	switch rule.Type {
+	case RuleBalanceBelow:
+		firing = entry.Amount < rule.Threshold
+		message = fmt.Sprintf("balance %.2f below threshold %.2f", entry.Amount, rule.Threshold)
+		return firing, entry.Amount, message
+
+	case RuleSpendAboveDailyAvgX:
+		limit := stats.Average * rule.Threshold
+		firing = stats.Average > 0 && stats.Spend > limit
+		message = fmt.Sprintf("spend %.2f exceeds %.1fx daily average (%.2f)", stats.Spend, rule.Threshold, limit)
+		return firing, stats.Spend, message
+
+	case RuleCurrencyChange:
+		firing = state.LastCurrency != "" && entry.Currency != state.LastCurrency
+		message = fmt.Sprintf("currency changed from %q to %q", state.LastCurrency, entry.Currency)
+		return firing, 0, message
+
+	default:
+		return false, 0, ""
+	}
+}
+
+// transition records the new firing state for key and, on a firing/resolved
+// change, dispatches the corresponding event to every sink.
+func (e *Engine) transition(ctx context.Context, serviceName, ruleType string, threshold float64, firing bool, value float64, message string, state *serviceState) error {
+	e.mu.Lock()
+	wasFiring := state.RuleFiring[ruleType]
+	state.RuleFiring[ruleType] = firing
+	e.mu.Unlock()
+
+	if firing == wasFiring {
+		return nil
+	}
+
+	eventState := "resolved"
+	if firing {
+		eventState = "firing"
+	}
+
+	event := Event{
+		Service:   serviceName,
+		Rule:      ruleType,
+		State:     eventState,
+		Message:   message,
+		Value:     value,
+		Threshold: threshold,
+		Timestamp: time.Now(),
+	}
+
+	return e.dispatch(ctx, event)
+}
+
+func (e *Engine) dispatch(ctx context.Context, event Event) error {
+	var errs []error
+	for _, sink := range e.sinks {
+		if err := sink.Deliver(ctx, event); err != nil {
+			errs = append(errs, err)
+			if e.logger != nil {
+				e.logger.Error("Alert sink delivery failed", "service", event.Service, "rule", event.Rule, "error", err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (e *Engine) stateFor(stateKey string) *serviceState {
+	state, ok := e.states[stateKey]
+	if ok {
+		return state
+	}
+
+	state = e.load(stateKey)
+	e.states[stateKey] = state
+	return state
+}
+
+func (e *Engine) statePath(stateKey string) string {
+	return filepath.Join(e.persistDir, sanitizeFilename(stateKey)+".alerts.json")
+}
+
+func (e *Engine) load(stateKey string) *serviceState {
+	if e.persistDir == "" {
+		return newServiceState()
+	}
+
+	data, err := os.ReadFile(e.statePath(stateKey))
+	if err != nil {
+		return newServiceState()
+	}
+
+	state := newServiceState()
+	if err := json.Unmarshal(data, state); err != nil {
+		return newServiceState()
+	}
+	if state.RuleFiring == nil {
+		state.RuleFiring = make(map[string]bool)
+	}
+	return state
+}
+
+func (e *Engine) persist(stateKey string, state *serviceState) {
+	if e.persistDir == "" {
+		return
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		if e.logger != nil {
+			e.logger.Error("Encode alert state failed", "state_key", stateKey, "error", err)
+		}
+		return
+	}
+
+	if err := os.MkdirAll(e.persistDir, 0o755); err != nil {
+		if e.logger != nil {
+			e.logger.Error("Create alert state dir failed", "state_key", stateKey, "error", err)
+		}
+		return
+	}
+
+	if err := os.WriteFile(e.statePath(stateKey), data, 0o644); err != nil {
+		if e.logger != nil {
+			e.logger.Error("Write alert state failed", "state_key", stateKey, "error", err)
+		}
+	}
+}
+
+func sanitizeFilename(name string) string {
+	var builder strings.Builder
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			builder.WriteRune(r)
+		default:
+			builder.WriteRune('_')
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,30 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sarff/FundsPulse/internal/notify"
+)
+
+// NotifierSink delivers alert events through the already-configured notify
+// transports (Telegram, Discord, Slack, email), so operators who only set up
+// routine notifications still see alerts without a dedicated webhook.
+type NotifierSink struct {
+	notifier notify.Notifier
+}
+
+// NewNotifierSink wraps notifier as an alert Sink.
+func NewNotifierSink(notifier notify.Notifier) *NotifierSink {
+	return &NotifierSink{notifier: notifier}
+}
+
+// Deliver formats event as plain text and sends it via NotifyStatic.
+func (s *NotifierSink) Deliver(ctx context.Context, event Event) error {
+	icon := "🔥"
+	if event.State == "resolved" {
+		icon = "✅"
+	}
+	message := fmt.Sprintf("%s Alert %s: %s\nService: %s\nRule: %s", icon, event.State, event.Message, event.Service, event.Rule)
+	return s.notifier.NotifyStatic(ctx, message)
+}
@@ -0,0 +1,121 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WebhookSink delivers alert events as a JSON POST, optionally signed with
+// HMAC-SHA256, retrying with exponential backoff on delivery failure.
+type WebhookSink struct {
+	url          string
+	secret       string
+	signatureHdr string
+	maxRetries   int
+	http         *http.Client
+}
+
+type webhookEventPayload struct {
+	Service   string    `json:"service"`
+	Rule      string    `json:"rule"`
+	State     string    `json:"state"`
+	Message   string    `json:"message"`
+	Value     float64   `json:"value"`
+	Threshold float64   `json:"threshold"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NewWebhookSink builds a webhook sink. When secret is non-empty, every
+// request carries an HMAC-SHA256 signature in signatureHdr (default
+// "X-FundsPulse-Alert-Signature"). maxRetries defaults to 3.
+func NewWebhookSink(url, secret, signatureHdr string, maxRetries int) (*WebhookSink, error) {
+	if url == "" {
+		return nil, errors.New("alert webhook url is required")
+	}
+	if signatureHdr == "" {
+		signatureHdr = "X-FundsPulse-Alert-Signature"
+	}
+	if maxRetries <= 0 {
+		maxRetries = 3
+	}
+	return &WebhookSink{
+		url:          url,
+		secret:       secret,
+		signatureHdr: signatureHdr,
+		maxRetries:   maxRetries,
+		http:         &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Deliver posts event, retrying with exponential backoff (500ms, 1s, 2s, ...)
+// until maxRetries attempts are exhausted.
+func (s *WebhookSink) Deliver(ctx context.Context, event Event) error {
+	body, err := json.Marshal(webhookEventPayload{
+		Service:   event.Service,
+		Rule:      event.Rule,
+		State:     event.State,
+		Message:   event.Message,
+		Value:     event.Value,
+		Threshold: event.Threshold,
+		Timestamp: event.Timestamp,
+	})
+	if err != nil {
+		return fmt.Errorf("encode alert payload: %v", err)
+	}
+
+	backoff := 500 * time.Millisecond
+	var lastErr error
+	for attempt := 0; attempt < s.maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		if lastErr = s.post(ctx, body); lastErr == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("deliver alert webhook after %d attempts: %v", s.maxRetries, lastErr)
+}
+
+func (s *WebhookSink) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build alert webhook request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if s.secret != "" {
+		req.Header.Set(s.signatureHdr, signBody(s.secret, body))
+	}
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("send alert webhook: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("send alert webhook: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func signBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
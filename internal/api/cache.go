@@ -0,0 +1,60 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// cacheEntry holds a rendered JSON body alongside the ETag derived from it.
+type cacheEntry struct {
+	body      []byte
+	etag      string
+	expiresAt time.Time
+}
+
+// cache is a small in-memory TTL cache keyed by request URL (path and
+// query), avoiding a
+// history-file re-read on every poll from a dashboard or Grafana datasource.
+type cache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cacheEntry
+}
+
+func newCache(ttl time.Duration) *cache {
+	return &cache{ttl: ttl, entries: make(map[string]cacheEntry)}
+}
+
+// get returns the cached entry for key if it hasn't expired.
+func (c *cache) get(key string) (cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+// set stores body under key, computing its ETag and expiry.
+func (c *cache) set(key string, body []byte) cacheEntry {
+	entry := cacheEntry{
+		body:      body,
+		etag:      etagFor(body),
+		expiresAt: time.Now().Add(c.ttl),
+	}
+
+	c.mu.Lock()
+	c.entries[key] = entry
+	c.mu.Unlock()
+
+	return entry
+}
+
+func etagFor(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:8]) + `"`
+}
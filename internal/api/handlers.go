@@ -0,0 +1,279 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sarff/FundsPulse/internal/breaker"
+	"github.com/sarff/FundsPulse/internal/config"
+	"github.com/sarff/FundsPulse/internal/history"
+)
+
+type serviceInfo struct {
+	Name        string `json:"name"`
+	Currency    string `json:"currency"`
+	BillingMode string `json:"billing_mode"`
+}
+
+type balanceResponse struct {
+	Service   string  `json:"service"`
+	Currency  string  `json:"currency"`
+	Balance   float64 `json:"balance"`
+	Average   float64 `json:"average_daily"`
+	MTDSpend  float64 `json:"mtd_spend"`
+	UpdatedAt string  `json:"updated_at"`
+}
+
+type historyResponse struct {
+	Service string               `json:"service"`
+	Days    int                  `json:"days,omitempty"`
+	From    string               `json:"from,omitempty"`
+	To      string               `json:"to,omitempty"`
+	Spends  []history.DailySpend `json:"spends"`
+}
+
+type overviewResponse struct {
+	GeneratedAt         time.Time          `json:"generated_at"`
+	ServiceCount        int                `json:"service_count"`
+	BalanceTotals       map[string]float64 `json:"balance_totals_by_currency"`
+	AverageSpends       map[string]float64 `json:"average_daily_spend_by_currency"`
+	UnsupportedServices []string           `json:"unsupported_services,omitempty"`
+}
+
+// isMultiEntryService reports whether svc may spread its history across
+// several checker.historyPathForEntry-suffixed files rather than a single
+// record at svc.HistoryFile (see response.multiple). Endpoints that read a
+// single bare-path record can't yet aggregate those, so they should refuse
+// to serve one rather than silently report a zeroed/empty record.
+func isMultiEntryService(svc config.ServiceConfig) bool {
+	return svc.Response.Multiple
+}
+
+func (s *Server) handleServices(w http.ResponseWriter, r *http.Request) {
+	s.respondJSON(w, r, r.URL.String(), func() (any, error) {
+		services := make([]serviceInfo, 0, len(s.cfg.Services))
+		for _, svc := range s.cfg.Services {
+			services = append(services, serviceInfo{
+				Name:        svc.Name,
+				Currency:    svc.CurrencySymbol,
+				BillingMode: svc.BillingMode,
+			})
+		}
+		return services, nil
+	})
+}
+
+func (s *Server) handleServiceBalance(w http.ResponseWriter, r *http.Request) {
+	svc, ok := s.findService(r.PathValue("name"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if isMultiEntryService(svc) {
+		http.Error(w, fmt.Sprintf("service %q: multi-entry services are not yet supported by this endpoint", svc.Name), http.StatusNotImplemented)
+		return
+	}
+
+	s.respondJSON(w, r, r.URL.String(), func() (any, error) {
+		record, err := s.history.Load(svc.HistoryFile)
+		if err != nil {
+			return nil, err
+		}
+
+		currency := svc.CurrencySymbol
+		return balanceResponse{
+			Service:   svc.Name,
+			Currency:  currency,
+			Balance:   record.LastBalance,
+			Average:   record.EWMAMean,
+			MTDSpend:  record.MonthToDateSpend,
+			UpdatedAt: record.LastUpdated,
+		}, nil
+	})
+}
+
+// handleServiceHistory serves either an arbitrary [from, to] window via
+// Manager.Query (unbounded by the fixed averaging window, the whole point of
+// a SQL-backed history.Store), when either query param is set, or the
+// original fixed-window view over Manager.Load's capped DailySpends.
+func (s *Server) handleServiceHistory(w http.ResponseWriter, r *http.Request) {
+	svc, ok := s.findService(r.PathValue("name"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if isMultiEntryService(svc) {
+		http.Error(w, fmt.Sprintf("service %q: multi-entry services are not yet supported by this endpoint", svc.Name), http.StatusNotImplemented)
+		return
+	}
+
+	fromRaw := r.URL.Query().Get("from")
+	toRaw := r.URL.Query().Get("to")
+	if fromRaw != "" || toRaw != "" {
+		from, to, err := parseHistoryRange(fromRaw, toRaw)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		s.respondJSON(w, r, r.URL.String(), func() (any, error) {
+			spends, err := s.history.Query(svc.HistoryFile, from, to)
+			if err != nil {
+				return nil, err
+			}
+			return historyResponse{
+				Service: svc.Name,
+				From:    from.Format("2006-01-02"),
+				To:      to.Format("2006-01-02"),
+				Spends:  spends,
+			}, nil
+		})
+		return
+	}
+
+	days := s.cfg.DaysForAverage
+	if raw := r.URL.Query().Get("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "days must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		days = parsed
+	}
+
+	s.respondJSON(w, r, r.URL.String(), func() (any, error) {
+		record, err := s.history.Load(svc.HistoryFile)
+		if err != nil {
+			return nil, err
+		}
+
+		spends := record.DailySpends
+		if len(spends) > days {
+			spends = spends[len(spends)-days:]
+		}
+
+		return historyResponse{Service: svc.Name, Days: days, Spends: spends}, nil
+	})
+}
+
+// parseHistoryRange parses the "from"/"to" query params (YYYY-MM-DD),
+// defaulting an empty "to" to now and an empty "from" to 30 days before to.
+func parseHistoryRange(fromRaw, toRaw string) (time.Time, time.Time, error) {
+	to := time.Now()
+	if toRaw != "" {
+		parsed, err := time.Parse("2006-01-02", toRaw)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("to must be formatted YYYY-MM-DD")
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, -30)
+	if fromRaw != "" {
+		parsed, err := time.Parse("2006-01-02", fromRaw)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("from must be formatted YYYY-MM-DD")
+		}
+		from = parsed
+	}
+
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("to must not be before from")
+	}
+	return from, to, nil
+}
+
+func (s *Server) handleServiceBreaker(w http.ResponseWriter, r *http.Request) {
+	svc, ok := s.findService(r.PathValue("name"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.respondJSON(w, r, r.URL.String(), func() (any, error) {
+		snap := breaker.Snapshot{State: breaker.StateClosed}
+		if s.breakers != nil {
+			if found, ok := s.breakers.BreakerSnapshot(svc.Name); ok {
+				snap = found
+			}
+		}
+		return struct {
+			Service string `json:"service"`
+			breaker.Snapshot
+		}{Service: svc.Name, Snapshot: snap}, nil
+	})
+}
+
+func (s *Server) handleOverview(w http.ResponseWriter, r *http.Request) {
+	s.respondJSON(w, r, r.URL.String(), func() (any, error) {
+		totals := make(map[string]float64)
+		averages := make(map[string]float64)
+		var unsupported []string
+
+		for _, svc := range s.cfg.Services {
+			if isMultiEntryService(svc) {
+				unsupported = append(unsupported, svc.Name)
+				continue
+			}
+
+			record, err := s.history.Load(svc.HistoryFile)
+			if err != nil {
+				return nil, err
+			}
+			currency := svc.CurrencySymbol
+			totals[currency] += record.LastBalance
+			averages[currency] += record.EWMAMean
+		}
+
+		return overviewResponse{
+			GeneratedAt:         time.Now(),
+			ServiceCount:        len(s.cfg.Services),
+			BalanceTotals:       totals,
+			AverageSpends:       averages,
+			UnsupportedServices: unsupported,
+		}, nil
+	})
+}
+
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]string{"status": "ok"})
+}
+
+// respondJSON serves a cached rendering of build() for key, honoring
+// If-None-Match against the cached ETag.
+func (s *Server) respondJSON(w http.ResponseWriter, r *http.Request, key string, build func() (any, error)) {
+	entry, ok := s.cache.get(key)
+	if !ok {
+		value, err := build()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		body, err := json.Marshal(value)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		entry = s.cache.set(key, body)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", entry.etag)
+
+	if match := r.Header.Get("If-None-Match"); match != "" && match == entry.etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	_, _ = w.Write(entry.body)
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,85 @@
+// Package api exposes a read-only JSON HTTP API over the history.Manager
+// records kept by the checker, so a dashboard or a Grafana JSON datasource
+// can pull balances and spend trends without shelling out to the binary.
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/sarff/iSlogger"
+
+	"github.com/sarff/FundsPulse/internal/breaker"
+	"github.com/sarff/FundsPulse/internal/config"
+	"github.com/sarff/FundsPulse/internal/history"
+)
+
+// breakerSource reports a service's current circuit breaker state. Satisfied
+// by *checker.Checker; kept as an interface here to avoid an api -> checker
+// import cycle (checker already imports nothing from api).
+type breakerSource interface {
+	BreakerSnapshot(name string) (breaker.Snapshot, bool)
+}
+
+// Server serves the read-only JSON API.
+type Server struct {
+	cfg      *config.Config
+	history  *history.Manager
+	breakers breakerSource
+	cache    *cache
+	logger   *iSlogger.Logger
+	http     *http.Server
+}
+
+// NewServer builds an API server bound to addr (e.g. ":8081"), caching
+// rendered responses for cacheTTL before re-reading history files. breakers
+// may be nil to omit the /services/{name}/breaker endpoint's live data (it
+// then always reports "closed").
+func NewServer(addr string, cfg *config.Config, historyManager *history.Manager, breakers breakerSource, cacheTTL time.Duration, logger *iSlogger.Logger) *Server {
+	s := &Server{cfg: cfg, history: historyManager, breakers: breakers, cache: newCache(cacheTTL), logger: logger}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /services", s.handleServices)
+	mux.HandleFunc("GET /services/{name}/balance", s.handleServiceBalance)
+	mux.HandleFunc("GET /services/{name}/history", s.handleServiceHistory)
+	mux.HandleFunc("GET /services/{name}/breaker", s.handleServiceBreaker)
+	mux.HandleFunc("GET /overview", s.handleOverview)
+	mux.HandleFunc("GET /healthz", s.handleHealthz)
+
+	s.http = &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return s
+}
+
+// Start runs the HTTP server until ctx is cancelled.
+func (s *Server) Start(ctx context.Context) error {
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := s.http.Shutdown(shutdownCtx); err != nil {
+			s.logger.Error("shutdown api server", "error", err)
+		}
+	}()
+
+	s.logger.Info("API listening", "addr", s.http.Addr)
+	if err := s.http.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// findService looks up a configured service by name.
+func (s *Server) findService(name string) (config.ServiceConfig, bool) {
+	for _, svc := range s.cfg.Services {
+		if svc.Name == name {
+			return svc, true
+		}
+	}
+	return config.ServiceConfig{}, false
+}
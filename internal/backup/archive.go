@@ -0,0 +1,143 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// buildArchive tars+gzips every file under historyDir (stored under a
+// "history/" prefix) and, when configSnapshot is non-empty, appends it as
+// "config.redacted.yaml".
+func buildArchive(historyDir string, configSnapshot []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	walkErr := filepath.WalkDir(historyDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(historyDir, path)
+		if err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if err := tw.WriteHeader(&tar.Header{
+			Name: filepath.ToSlash(filepath.Join("history", rel)),
+			Mode: 0o644,
+			Size: int64(len(data)),
+		}); err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		return err
+	})
+	if walkErr != nil && !os.IsNotExist(walkErr) {
+		return nil, fmt.Errorf("walk history dir: %v", walkErr)
+	}
+
+	if len(configSnapshot) > 0 {
+		if err := tw.WriteHeader(&tar.Header{
+			Name: "config.redacted.yaml",
+			Mode: 0o644,
+			Size: int64(len(configSnapshot)),
+		}); err != nil {
+			return nil, fmt.Errorf("write config header: %v", err)
+		}
+		if _, err := tw.Write(configSnapshot); err != nil {
+			return nil, fmt.Errorf("write config snapshot: %v", err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("close tar writer: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("close gzip writer: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// extractArchive restores every "history/" entry from archive into historyDir.
+func extractArchive(archive io.Reader, historyDir string) error {
+	gz, err := gzip.NewReader(archive)
+	if err != nil {
+		return fmt.Errorf("open gzip reader: %v", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read tar entry: %v", err)
+		}
+
+		rel, ok := strings.CutPrefix(hdr.Name, "history/")
+		if !ok {
+			continue
+		}
+
+		dest, err := safeJoin(historyDir, filepath.FromSlash(rel))
+		if err != nil {
+			return fmt.Errorf("tar entry %q: %v", hdr.Name, err)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return fmt.Errorf("create restore dir: %v", err)
+		}
+
+		file, err := os.Create(dest)
+		if err != nil {
+			return fmt.Errorf("create restore file %q: %v", dest, err)
+		}
+		if _, err := io.Copy(file, tr); err != nil {
+			file.Close()
+			return fmt.Errorf("write restore file %q: %v", dest, err)
+		}
+		if err := file.Close(); err != nil {
+			return fmt.Errorf("close restore file %q: %v", dest, err)
+		}
+	}
+}
+
+// safeJoin joins rel onto dir and rejects the result if rel (via "..",
+// absolute paths, or symlink-free traversal) would resolve outside dir, so a
+// crafted or corrupted archive can't write files elsewhere on disk
+// (zip-slip).
+func safeJoin(dir, rel string) (string, error) {
+	joined := filepath.Join(dir, rel)
+
+	cleanDir, err := filepath.Abs(filepath.Clean(dir))
+	if err != nil {
+		return "", fmt.Errorf("resolve base dir: %v", err)
+	}
+	cleanJoined, err := filepath.Abs(filepath.Clean(joined))
+	if err != nil {
+		return "", fmt.Errorf("resolve entry path: %v", err)
+	}
+
+	if cleanJoined != cleanDir && !strings.HasPrefix(cleanJoined, cleanDir+string(filepath.Separator)) {
+		return "", fmt.Errorf("entry %q escapes restore directory", rel)
+	}
+	return cleanJoined, nil
+}
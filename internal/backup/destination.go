@@ -0,0 +1,14 @@
+package backup
+
+import (
+	"context"
+	"io"
+)
+
+// Destination stores and retrieves backup archives by name.
+type Destination interface {
+	Upload(ctx context.Context, name string, data io.Reader) error
+	List(ctx context.Context) ([]string, error)
+	Download(ctx context.Context, name string) (io.ReadCloser, error)
+	Delete(ctx context.Context, name string) error
+}
@@ -0,0 +1,78 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// LocalDestination stores backup archives on the local filesystem.
+type LocalDestination struct {
+	dir string
+}
+
+// NewLocalDestination builds a destination rooted at dir.
+func NewLocalDestination(dir string) *LocalDestination {
+	return &LocalDestination{dir: dir}
+}
+
+// Upload writes data to dir/name, creating dir if needed.
+func (d *LocalDestination) Upload(ctx context.Context, name string, data io.Reader) error {
+	if err := os.MkdirAll(d.dir, 0o755); err != nil {
+		return fmt.Errorf("create backup dir: %v", err)
+	}
+
+	path := filepath.Join(d.dir, name)
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create backup file %q: %v", path, err)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, data); err != nil {
+		return fmt.Errorf("write backup file %q: %v", path, err)
+	}
+	return nil
+}
+
+// List returns every archive name stored under dir, sorted ascending.
+func (d *LocalDestination) List(ctx context.Context) ([]string, error) {
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("list backup dir: %v", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Download opens dir/name for reading.
+func (d *LocalDestination) Download(ctx context.Context, name string) (io.ReadCloser, error) {
+	path := filepath.Join(d.dir, name)
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open backup file %q: %v", path, err)
+	}
+	return file, nil
+}
+
+// Delete removes dir/name.
+func (d *LocalDestination) Delete(ctx context.Context, name string) error {
+	path := filepath.Join(d.dir, name)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("delete backup file %q: %v", path, err)
+	}
+	return nil
+}
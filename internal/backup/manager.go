@@ -0,0 +1,188 @@
+// Package backup periodically snapshots the history directory (and a
+// redacted copy of the loaded config) to a configured destination, and can
+// restore from any archive it produced.
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sarff/iSlogger"
+
+	"github.com/sarff/FundsPulse/internal/config"
+)
+
+// Manager owns the snapshot/restore/retention workflow for one destination.
+type Manager struct {
+	historyDir     string
+	configSnapshot []byte
+	destination    Destination
+	retainDaily    int
+	retainWeekly   int
+	logger         *iSlogger.Logger
+}
+
+// New builds a backup manager for cfg, bound to historyDir. configSnapshot
+// should already have secrets redacted by the caller (see config.Config.Redact).
+func New(cfg config.BackupConfig, historyDir string, configSnapshot []byte, logger *iSlogger.Logger) (*Manager, error) {
+	destination, err := buildDestination(cfg.Destination)
+	if err != nil {
+		return nil, err
+	}
+	return &Manager{
+		historyDir:     historyDir,
+		configSnapshot: configSnapshot,
+		destination:    destination,
+		retainDaily:    cfg.RetainDaily,
+		retainWeekly:   cfg.RetainWeekly,
+		logger:         logger,
+	}, nil
+}
+
+func buildDestination(cfg config.BackupDestinationConfig) (Destination, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.Kind)) {
+	case "local":
+		return NewLocalDestination(cfg.Local.Path), nil
+	case "s3":
+		return NewS3Destination(*cfg.S3)
+	case "rclone":
+		return NewRcloneDestination(cfg.Rclone.Remote), nil
+	default:
+		return nil, fmt.Errorf("backup: unknown destination kind %q", cfg.Kind)
+	}
+}
+
+// Snapshot archives the history dir, uploads it, and prunes old archives
+// according to the retention policy.
+func (m *Manager) Snapshot(ctx context.Context) error {
+	archive, err := buildArchive(m.historyDir, m.configSnapshot)
+	if err != nil {
+		return fmt.Errorf("build archive: %v", err)
+	}
+
+	name := fmt.Sprintf("fundspulse-backup-%s.tar.gz", time.Now().UTC().Format("20060102-150405"))
+	if err := m.destination.Upload(ctx, name, bytes.NewReader(archive)); err != nil {
+		return fmt.Errorf("upload archive: %v", err)
+	}
+
+	if m.logger != nil {
+		m.logger.Info("Backup snapshot stored", "name", name, "bytes", len(archive))
+	}
+
+	return m.applyRetention(ctx)
+}
+
+// Restore downloads archive by name and extracts it over the history directory.
+func (m *Manager) Restore(ctx context.Context, archive string) error {
+	reader, err := m.destination.Download(ctx, archive)
+	if err != nil {
+		return fmt.Errorf("download archive: %v", err)
+	}
+	defer reader.Close()
+
+	if err := extractArchive(reader, m.historyDir); err != nil {
+		return fmt.Errorf("extract archive: %v", err)
+	}
+	return nil
+}
+
+// archiveTimestampPattern pulls the "20060102-150405" timestamp out of a
+// name built by Snapshot (see the format string there).
+var archiveTimestampPattern = regexp.MustCompile(`(\d{8}-\d{6})`)
+
+// datedArchive pairs an archive name with the timestamp parsed from it.
+type datedArchive struct {
+	name string
+	at   time.Time
+}
+
+// applyRetention implements a standard daily/weekly (GFS-style) rotation:
+// the newest archive in each of the most recent retainDaily calendar days is
+// kept, as is the newest archive in each of the most recent retainWeekly ISO
+// weeks, and everything else is deleted. Archives whose name carries no
+// parseable timestamp are left alone rather than guessed at.
+func (m *Manager) applyRetention(ctx context.Context) error {
+	names, err := m.destination.List(ctx)
+	if err != nil {
+		return fmt.Errorf("list archives: %v", err)
+	}
+
+	keep := make(map[string]bool)
+	dated := make([]datedArchive, 0, len(names))
+	for _, name := range names {
+		at, ok := parseArchiveTimestamp(name)
+		if !ok {
+			keep[name] = true
+			continue
+		}
+		dated = append(dated, datedArchive{name: name, at: at})
+	}
+
+	sort.Slice(dated, func(i, j int) bool { return dated[i].at.After(dated[j].at) })
+
+	for name := range newestPerBucket(dated, m.retainDaily, dayBucket) {
+		keep[name] = true
+	}
+	for name := range newestPerBucket(dated, m.retainWeekly, weekBucket) {
+		keep[name] = true
+	}
+
+	for _, d := range dated {
+		if keep[d.name] {
+			continue
+		}
+		if err := m.destination.Delete(ctx, d.name); err != nil {
+			return fmt.Errorf("prune archive %q: %v", d.name, err)
+		}
+	}
+	return nil
+}
+
+func parseArchiveTimestamp(name string) (time.Time, bool) {
+	match := archiveTimestampPattern.FindStringSubmatch(name)
+	if match == nil {
+		return time.Time{}, false
+	}
+	at, err := time.Parse("20060102-150405", match[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return at, true
+}
+
+func dayBucket(at time.Time) string {
+	return at.UTC().Format("2006-01-02")
+}
+
+func weekBucket(at time.Time) string {
+	year, week := at.UTC().ISOWeek()
+	return fmt.Sprintf("%04d-W%02d", year, week)
+}
+
+// newestPerBucket walks dated (expected newest-first) and keeps the newest
+// archive in each of the first limit distinct buckets bucketFn produces.
+func newestPerBucket(dated []datedArchive, limit int, bucketFn func(time.Time) string) map[string]bool {
+	kept := make(map[string]bool)
+	if limit <= 0 {
+		return kept
+	}
+
+	seen := make(map[string]bool)
+	for _, d := range dated {
+		key := bucketFn(d.at)
+		if seen[key] {
+			continue
+		}
+		if len(seen) >= limit {
+			break
+		}
+		seen[key] = true
+		kept[d.name] = true
+	}
+	return kept
+}
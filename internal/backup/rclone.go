@@ -0,0 +1,86 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// RcloneDestination stores backup archives via a pre-configured rclone remote
+// (e.g. "myremote:bucket/path"), shelling out to the rclone binary.
+type RcloneDestination struct {
+	remote string
+}
+
+// NewRcloneDestination builds a destination bound to remote.
+func NewRcloneDestination(remote string) *RcloneDestination {
+	return &RcloneDestination{remote: remote}
+}
+
+func (d *RcloneDestination) path(name string) string {
+	return strings.TrimSuffix(d.remote, "/") + "/" + name
+}
+
+// Upload spools data to a temp file and rclone-copies it to remote/name.
+func (d *RcloneDestination) Upload(ctx context.Context, name string, data io.Reader) error {
+	tmp, err := os.CreateTemp("", "fundspulse-backup-*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write temp file: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp file: %v", err)
+	}
+
+	cmd := exec.CommandContext(ctx, "rclone", "copyto", tmp.Name(), d.path(name))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("rclone copyto: %v: %s", err, output)
+	}
+	return nil
+}
+
+// List shells out to "rclone lsf" against remote.
+func (d *RcloneDestination) List(ctx context.Context) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "rclone", "lsf", d.remote)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("rclone lsf: %v", err)
+	}
+
+	var names []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			names = append(names, line)
+		}
+	}
+	return names, nil
+}
+
+// Download shells out to "rclone cat" and buffers remote/name in memory.
+func (d *RcloneDestination) Download(ctx context.Context, name string) (io.ReadCloser, error) {
+	cmd := exec.CommandContext(ctx, "rclone", "cat", d.path(name))
+	var buf bytes.Buffer
+	cmd.Stdout = &buf
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("rclone cat: %v", err)
+	}
+	return io.NopCloser(&buf), nil
+}
+
+// Delete shells out to "rclone deletefile" against remote/name.
+func (d *RcloneDestination) Delete(ctx context.Context, name string) error {
+	cmd := exec.CommandContext(ctx, "rclone", "deletefile", d.path(name))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("rclone deletefile: %v: %s", err, output)
+	}
+	return nil
+}
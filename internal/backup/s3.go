@@ -0,0 +1,90 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"github.com/sarff/FundsPulse/internal/config"
+)
+
+// S3Destination stores backup archives in an S3-compatible object store.
+type S3Destination struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+// NewS3Destination builds a destination from an S3-compatible endpoint.
+func NewS3Destination(cfg config.BackupS3Config) (*S3Destination, error) {
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: true,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create s3 client: %v", err)
+	}
+	return &S3Destination{client: client, bucket: cfg.Bucket, prefix: cfg.Prefix}, nil
+}
+
+func (d *S3Destination) key(name string) string {
+	if d.prefix == "" {
+		return name
+	}
+	return d.prefix + "/" + name
+}
+
+// Upload puts data at bucket/prefix/name.
+func (d *S3Destination) Upload(ctx context.Context, name string, data io.Reader) error {
+	buf, err := io.ReadAll(data)
+	if err != nil {
+		return fmt.Errorf("read backup data: %v", err)
+	}
+
+	_, err = d.client.PutObject(ctx, d.bucket, d.key(name), bytes.NewReader(buf), int64(len(buf)), minio.PutObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("upload s3 object %q: %v", name, err)
+	}
+	return nil
+}
+
+// List returns every archive name under bucket/prefix, with prefix stripped
+// so callers (retention pruning, Download, Delete) deal in bare names the
+// same way LocalDestination and RcloneDestination already do.
+func (d *S3Destination) List(ctx context.Context) ([]string, error) {
+	var names []string
+	for obj := range d.client.ListObjects(ctx, d.bucket, minio.ListObjectsOptions{Prefix: d.prefix}) {
+		if obj.Err != nil {
+			return nil, fmt.Errorf("list s3 objects: %v", obj.Err)
+		}
+		name := obj.Key
+		if d.prefix != "" {
+			name = strings.TrimPrefix(strings.TrimPrefix(name, d.prefix), "/")
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// Download opens bucket/prefix/name for reading.
+func (d *S3Destination) Download(ctx context.Context, name string) (io.ReadCloser, error) {
+	obj, err := d.client.GetObject(ctx, d.bucket, d.key(name), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("download s3 object %q: %v", name, err)
+	}
+	return obj, nil
+}
+
+// Delete removes bucket/prefix/name.
+func (d *S3Destination) Delete(ctx context.Context, name string) error {
+	if err := d.client.RemoveObject(ctx, d.bucket, d.key(name), minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("delete s3 object %q: %v", name, err)
+	}
+	return nil
+}
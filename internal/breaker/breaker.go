@@ -0,0 +1,104 @@
+// Package breaker implements a simple per-dependency circuit breaker: it
+// opens after a run of consecutive failures, then half-opens after a reset
+// timeout to let a single probe call through before deciding whether to
+// close again.
+package breaker
+
+import (
+	"sync"
+	"time"
+)
+
+// State is a Breaker's current state.
+type State string
+
+const (
+	StateClosed   State = "closed"
+	StateOpen     State = "open"
+	StateHalfOpen State = "half_open"
+)
+
+// Breaker guards a single dependency (e.g. one configured service). It is
+// safe for concurrent use.
+type Breaker struct {
+	failureThreshold int
+	resetTimeout     time.Duration
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	lastErr  error
+}
+
+// New builds a breaker that opens after failureThreshold consecutive
+// failures and half-opens resetTimeout after that.
+func New(failureThreshold int, resetTimeout time.Duration) *Breaker {
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+	if resetTimeout <= 0 {
+		resetTimeout = time.Minute
+	}
+	return &Breaker{failureThreshold: failureThreshold, resetTimeout: resetTimeout, state: StateClosed}
+}
+
+// Allow reports whether a call may proceed right now, moving an Open breaker
+// to HalfOpen once resetTimeout has elapsed so the next call can probe it.
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && time.Since(b.openedAt) >= b.resetTimeout {
+		b.state = StateHalfOpen
+	}
+	return b.state != StateOpen
+}
+
+// Success records a call that succeeded, closing the breaker and resetting
+// its failure count.
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.state = StateClosed
+	b.lastErr = nil
+}
+
+// Failure records a call that failed with err, opening the breaker once
+// failureThreshold consecutive failures have accumulated (including a failed
+// probe while HalfOpen). It returns true when this failure just opened it.
+func (b *Breaker) Failure(err error) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.lastErr = err
+
+	if b.state == StateHalfOpen || b.failures >= b.failureThreshold {
+		wasOpen := b.state == StateOpen
+		b.state = StateOpen
+		b.openedAt = time.Now()
+		return !wasOpen
+	}
+	return false
+}
+
+// Snapshot is a point-in-time view of a Breaker's state, safe to serialize.
+type Snapshot struct {
+	State    State  `json:"state"`
+	Failures int    `json:"failures"`
+	LastErr  string `json:"last_error,omitempty"`
+}
+
+// Snapshot reports b's current state without mutating it.
+func (b *Breaker) Snapshot() Snapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	snap := Snapshot{State: b.state, Failures: b.failures}
+	if b.lastErr != nil {
+		snap.LastErr = b.lastErr.Error()
+	}
+	return snap
+}
@@ -6,49 +6,84 @@ import (
 	"math"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/reugn/go-quartz/job"
 	"github.com/reugn/go-quartz/quartz"
 	"github.com/sarff/iSlogger"
 
+	"github.com/sarff/FundsPulse/internal/alerts"
+	"github.com/sarff/FundsPulse/internal/backup"
+	"github.com/sarff/FundsPulse/internal/breaker"
 	"github.com/sarff/FundsPulse/internal/config"
 	"github.com/sarff/FundsPulse/internal/history"
 	"github.com/sarff/FundsPulse/internal/notify"
 	"github.com/sarff/FundsPulse/internal/service"
+	"github.com/sarff/FundsPulse/internal/webserver"
 )
 
 // Checker coordinates balance polling and notification workflow.
 type Checker struct {
 	cfg      *config.Config
-	client   *service.Client
+	fetchers *service.Registry
 	history  *history.Manager
-	notifier *notify.Telegram
+	notifier notify.Notifier
 	logger   *iSlogger.Logger
 	location *time.Location
+	store    *webserver.Store
+	backup   *backup.Manager
+	alerts   *alerts.Engine
+
+	breakersMu sync.Mutex
+	breakers   map[string]*breaker.Breaker
+}
+
+// serviceResult collects one configured service's check outcome, gathered
+// from the concurrent worker pool in RunOnce and replayed in configured
+// order for notification.
+type serviceResult struct {
+	name   string
+	report string
+	err    error
 }
 
 type balanceReport struct {
-	Currency string
-	Balance  float64
-	Average  float64
-	DaysLeft float64
-	Warn     bool
+	Currency       string
+	Balance        float64
+	Average        float64
+	DaysLeft       float64
+	Warn           bool
+	MTDSpend       float64
+	BudgetLimit    float64
+	ProjectedSpend float64
+	BudgetWarn     bool
+	Delta          float64
+	ZScore         float64
+	ExpectedLow    float64
+	ExpectedHigh   float64
+	AnomalyWarn    bool
 }
 
-// New constructs checker instance.
-func New(cfg *config.Config, client *service.Client, history *history.Manager, notifier *notify.Telegram, logger *iSlogger.Logger) (*Checker, error) {
+// New constructs checker instance. store may be nil when the dashboard is
+// disabled, backupMgr may be nil when backups are disabled, and alertEngine
+// may be nil when alerts are disabled.
+func New(cfg *config.Config, fetchers *service.Registry, history *history.Manager, notifier notify.Notifier, logger *iSlogger.Logger, store *webserver.Store, backupMgr *backup.Manager, alertEngine *alerts.Engine) (*Checker, error) {
 	loc, err := cfg.Schedule.Location()
 	if err != nil {
 		return nil, err
 	}
 	return &Checker{
 		cfg:      cfg,
-		client:   client,
+		fetchers: fetchers,
 		history:  history,
 		notifier: notifier,
 		logger:   logger,
 		location: loc,
+		store:    store,
+		backup:   backupMgr,
+		alerts:   alertEngine,
+		breakers: make(map[string]*breaker.Breaker),
 	}, nil
 }
 
@@ -83,6 +118,12 @@ func (c *Checker) Start(ctx context.Context) error {
 		return fmt.Errorf("schedule job: %v", err)
 	}
 
+	if c.backup != nil {
+		if err := c.scheduleBackupJob(scheduler); err != nil {
+			return err
+		}
+	}
+
 	c.logger.Info("Scheduler started", "cron", cronExpr, "location", c.location.String())
 
 	<-ctx.Done()
@@ -95,27 +136,53 @@ func (c *Checker) Start(ctx context.Context) error {
 	return nil
 }
 
+// scheduleBackupJob registers c.backup's snapshot job on scheduler, driven by
+// cfg.Backup.Cron.
+func (c *Checker) scheduleBackupJob(scheduler quartz.Scheduler) error {
+	cronExpr := c.cfg.Backup.Cron
+
+	backupJob := job.NewFunctionJob(func(ctx context.Context) (any, error) {
+		if err := c.backup.Snapshot(ctx); err != nil {
+			c.logger.Error("Backup snapshot failed", "error", err)
+			return nil, err
+		}
+		return nil, nil
+	})
+
+	jobDetail := quartz.NewJobDetail(backupJob, quartz.NewJobKey("history_backup"))
+	trigger, err := quartz.NewCronTriggerWithLoc(cronExpr, c.location)
+	if err != nil {
+		return fmt.Errorf("create backup cron trigger: %v", err)
+	}
+
+	if err := scheduler.ScheduleJob(jobDetail, trigger); err != nil {
+		return fmt.Errorf("schedule backup job: %v", err)
+	}
+
+	c.logger.Info("Backup scheduler started", "cron", cronExpr)
+	return nil
+}
+
 // RunOnce performs balance check immediately.
 func (c *Checker) RunOnce(ctx context.Context) error {
 	now := time.Now().In(c.location)
 	var firstErr error
 
-	for _, svc := range c.cfg.Services {
-		report, err := c.processService(ctx, svc, now)
-		if err != nil {
-			c.logger.Error("Service check failed", "service", svc.Name, "error", err)
+	for _, res := range c.fetchServicesConcurrently(ctx, now) {
+		c.recordService(res.name, res.report, res.err)
+		if res.err != nil {
+			c.logger.Error("Service check failed", "service", res.name, "error", res.err)
 			if firstErr == nil {
-				firstErr = err
+				firstErr = res.err
 			}
-			failureMsg := fmt.Sprintf("Service: %s\nError: %v", svc.Name, err)
-			if notifyErr := c.notifier.Notify(ctx, c.cfg.Telegram.ChatIDs, failureMsg); notifyErr != nil {
-				c.logger.Error("Failed to notify about error", "service", svc.Name, "error", notifyErr)
+			if notifyErr := c.notifier.NotifyError(ctx, res.name, res.err); notifyErr != nil {
+				c.logger.Error("Failed to notify about error", "service", res.name, "error", notifyErr)
 			}
 			continue
 		}
 
-		if err := c.notifier.Notify(ctx, c.cfg.Telegram.ChatIDs, report); err != nil {
-			c.logger.Error("Failed to notify", "service", svc.Name, "error", err)
+		if err := c.notifier.NotifyBalance(ctx, res.report); err != nil {
+			c.logger.Error("Failed to notify", "service", res.name, "error", err)
 			if firstErr == nil {
 				firstErr = err
 			}
@@ -124,10 +191,13 @@ func (c *Checker) RunOnce(ctx context.Context) error {
 
 	for _, svc := range c.cfg.StaticServices {
 		message, ok := c.processStaticService(svc, now)
+		if c.store != nil {
+			c.store.RecordStatic(svc.Name, message, ok)
+		}
 		if !ok {
 			continue
 		}
-		if err := c.notifier.Notify(ctx, c.cfg.Telegram.ChatIDs, message); err != nil {
+		if err := c.notifier.NotifyStatic(ctx, message); err != nil {
 			c.logger.Error("Failed to notify", "service", svc.Name, "error", err)
 			if firstErr == nil {
 				firstErr = err
@@ -135,12 +205,109 @@ func (c *Checker) RunOnce(ctx context.Context) error {
 		}
 	}
 
+	if c.store != nil {
+		c.store.RecordRun(now, firstErr)
+	}
+
 	return firstErr
 }
 
+func (c *Checker) recordService(name, message string, err error) {
+	if c.store == nil {
+		return
+	}
+	c.store.RecordService(name, message, err)
+}
+
+// fetchServicesConcurrently runs processService for every configured service
+// across a bounded worker pool (cfg.Concurrency.Workers), skipping services
+// whose circuit breaker is currently open. Results are returned in
+// cfg.Services order regardless of completion order, so notification stays
+// deterministic.
+func (c *Checker) fetchServicesConcurrently(ctx context.Context, now time.Time) []serviceResult {
+	workers := c.cfg.Concurrency.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]serviceResult, len(c.cfg.Services))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+
+	for i, svc := range c.cfg.Services {
+		wg.Add(1)
+		go func(i int, svc config.ServiceConfig) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i] = c.fetchServiceWithBreaker(ctx, svc, now)
+		}(i, svc)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// fetchServiceWithBreaker runs processService for svc unless its circuit
+// breaker is open, recording the outcome against the breaker so repeated
+// failures trip it and a later success closes it again.
+func (c *Checker) fetchServiceWithBreaker(ctx context.Context, svc config.ServiceConfig, now time.Time) serviceResult {
+	svcBreaker := c.breakerFor(svc.Name)
+
+	if !svcBreaker.Allow() {
+		err := fmt.Errorf("service %q: circuit breaker open, skipping fetch", svc.Name)
+		c.logger.Info("Circuit breaker open, skipping service check", "service", svc.Name)
+		return serviceResult{name: svc.Name, err: err}
+	}
+
+	report, err := c.processService(ctx, svc, now)
+	if err != nil {
+		if svcBreaker.Failure(err) {
+			c.logger.Error("Circuit breaker opened", "service", svc.Name, "error", err)
+		}
+	} else {
+		svcBreaker.Success()
+	}
+
+	return serviceResult{name: svc.Name, report: report, err: err}
+}
+
+func (c *Checker) breakerFor(name string) *breaker.Breaker {
+	c.breakersMu.Lock()
+	defer c.breakersMu.Unlock()
+
+	b, ok := c.breakers[name]
+	if !ok {
+		b = breaker.New(c.cfg.Concurrency.BreakerFailureThreshold, time.Duration(c.cfg.Concurrency.BreakerResetTimeoutSeconds)*time.Second)
+		c.breakers[name] = b
+	}
+	return b
+}
+
+// BreakerSnapshot reports the current circuit breaker state for name, for
+// the api package and dashboards. ok is false when the service has not been
+// checked yet (its breaker does not exist).
+func (c *Checker) BreakerSnapshot(name string) (breaker.Snapshot, bool) {
+	c.breakersMu.Lock()
+	defer c.breakersMu.Unlock()
+
+	b, ok := c.breakers[name]
+	if !ok {
+		return breaker.Snapshot{}, false
+	}
+	return b.Snapshot(), true
+}
+
 func (c *Checker) processService(ctx context.Context, svc config.ServiceConfig, now time.Time) (string, error) {
-	entries, err := c.client.FetchBalance(ctx, svc)
+	entries, err := c.fetchers.Fetch(ctx, svc)
 	if err != nil {
+		if c.alerts != nil {
+			if alertErr := c.alerts.RecordFetchError(ctx, svc.Name, svc.Alerts, err); alertErr != nil {
+				c.logger.Error("Failed to dispatch fetch-error alert", "service", svc.Name, "error", alertErr)
+			}
+		}
 		return "", err
 	}
 
@@ -158,28 +325,52 @@ func (c *Checker) processService(ctx context.Context, svc config.ServiceConfig,
 			historyPath = historyPathForEntry(svc.HistoryFile, idx, currency)
 		}
 
-		stats, statsErr := c.history.Update(historyPath, entry.Amount, now)
+		resetDay := 0
+		if svc.Budget != nil {
+			resetDay = svc.Budget.ResetDay
+		}
+		stats, statsErr := c.history.Update(historyPath, entry.Amount, entry.NativeAmount, entry.NativeCurrency, now, svc.BillingMode, resetDay)
 		if statsErr != nil {
 			return "", fmt.Errorf("update history: %v", statsErr)
 		}
 
+		alertKey := svc.Name
+		if multiple {
+			alertKey = alertStateKey(svc.Name, idx, currency)
+		}
+
+		if c.alerts != nil {
+			if alertErr := c.alerts.Evaluate(ctx, alertKey, svc, entry, stats); alertErr != nil {
+				c.logger.Error("Failed to dispatch alert", "service", svc.Name, "error", alertErr)
+			}
+		}
+
 		avg := stats.Average
 		daysLeft := math.Inf(1)
-		warn := false
+		warn := stats.AnomalyWarn
 
-		if svc.BillingMode != "postpaid" {
-			if avg > 0 {
-				daysLeft = entry.Amount / avg
-			}
-			warn = daysLeft != math.Inf(1) && daysLeft < c.cfg.MinimumDaysLeft
+		if svc.BillingMode != "postpaid" && avg > 0 {
+			daysLeft = entry.Amount / avg
 		}
 
+		budgetLimit, budgetWarn, projected := evaluateBudget(svc.Budget, avg, stats.MTDSpend, now)
+		warn = warn || budgetWarn
+
 		reports = append(reports, balanceReport{
-			Currency: currency,
-			Balance:  entry.Amount,
-			Average:  avg,
-			DaysLeft: daysLeft,
-			Warn:     warn,
+			Currency:       currency,
+			Balance:        entry.Amount,
+			Average:        avg,
+			DaysLeft:       daysLeft,
+			Warn:           warn,
+			MTDSpend:       stats.MTDSpend,
+			BudgetLimit:    budgetLimit,
+			ProjectedSpend: projected,
+			BudgetWarn:     budgetWarn,
+			Delta:          stats.Delta,
+			ZScore:         stats.ZScore,
+			ExpectedLow:    stats.ExpectedLow,
+			ExpectedHigh:   stats.ExpectedHigh,
+			AnomalyWarn:    stats.AnomalyWarn,
 		})
 
 		c.logger.Info(
@@ -237,6 +428,27 @@ func composeMessage(serviceName, billingMode string, entries []balanceReport) st
 		if billingMode != "postpaid" {
 			builder.WriteString(fmt.Sprintf("📆 Enough for: %s", formatDays(entry.DaysLeft)))
 		}
+		if entry.BudgetLimit > 0 {
+			budgetSuffix := ""
+			if entry.BudgetWarn {
+				budgetSuffix = " !!!"
+			}
+			builder.WriteString(fmt.Sprintf(
+				"\n💰 Budget: %s / %s MTD (projected %s)%s",
+				formatAmount(entry.MTDSpend, entry.Currency),
+				formatAmount(entry.BudgetLimit, entry.Currency),
+				formatAmount(entry.ProjectedSpend, entry.Currency),
+				budgetSuffix,
+			))
+		}
+		anomalySuffix := ""
+		if entry.AnomalyWarn {
+			anomalySuffix = " !!!"
+		}
+		builder.WriteString(fmt.Sprintf(
+			"\nΔ today: %.2f (expected %.2f ± %.2f, z=%.2f)%s",
+			entry.Delta, entry.Average, entry.ExpectedHigh-entry.Average, entry.ZScore, anomalySuffix,
+		))
 		if i < len(entries)-1 {
 			builder.WriteString("\n\n")
 		}
@@ -245,6 +457,32 @@ func composeMessage(serviceName, billingMode string, entries []balanceReport) st
 	return builder.String()
 }
 
+// evaluateBudget computes the end-of-cycle spend projection for svc and
+// reports whether it (or the already-spent portion) crosses the configured
+// budget. It returns a zero limit when no budget is configured. The cycle is
+// the calendar month, unless budget.ResetDay anchors it to a different
+// day-of-month (see history.BillingCycleEnd).
+func evaluateBudget(budget *config.BudgetConfig, avgDaily, mtdSpend float64, now time.Time) (limit float64, warn bool, projected float64) {
+	if budget == nil || budget.MonthlyLimit <= 0 {
+		return 0, false, 0
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	daysRemaining := history.BillingCycleEnd(now, budget.ResetDay).Sub(today).Hours() / 24
+	if daysRemaining < 0 {
+		daysRemaining = 0
+	}
+
+	projected = avgDaily*daysRemaining + mtdSpend
+	warn = projected > budget.MonthlyLimit
+
+	if budget.WarnPercent > 0 && mtdSpend >= budget.MonthlyLimit*budget.WarnPercent/100 {
+		warn = true
+	}
+
+	return budget.MonthlyLimit, warn, projected
+}
+
 func formatAmount(value float64, currency string) string {
 	if currency != "" {
 		switch currency {
@@ -299,6 +537,13 @@ func composeStaticMessage(svc config.StaticServiceConfig, kind string) string {
 	if strings.TrimSpace(svc.CardPay) != "" {
 		builder.WriteString(fmt.Sprintf("💳Card: %s\n", svc.CardPay))
 	}
+	if svc.Budget != nil && svc.Budget.MonthlyLimit > 0 && svc.Amount > svc.Budget.MonthlyLimit {
+		builder.WriteString(fmt.Sprintf(
+			"💰 Budget exceeded: %s > %s !!!\n",
+			formatAmount(svc.Amount, svc.CurrencySymbol),
+			formatAmount(svc.Budget.MonthlyLimit, svc.CurrencySymbol),
+		))
+	}
 	return strings.TrimSpace(builder.String())
 }
 
@@ -320,6 +565,17 @@ func historyPathForEntry(base string, index int, currency string) string {
 	return filepath.Join(dir, fmt.Sprintf("%s_%s", name, suffix))
 }
 
+// alertStateKey returns the per-entry key alerts.Engine should dedup and
+// persist firing state under, mirroring historyPathForEntry so a multi-entry
+// service's entries don't share (and clobber) each other's alert state.
+func alertStateKey(serviceName string, index int, currency string) string {
+	suffixParts := []string{fmt.Sprintf("%02d", index+1)}
+	if sanitized := sanitizeIdentifier(currency); sanitized != "" {
+		suffixParts = append(suffixParts, sanitized)
+	}
+	return serviceName + "_" + strings.Join(suffixParts, "_")
+}
+
 func sanitizeIdentifier(value string) string {
 	value = strings.TrimSpace(value)
 	if value == "" {
@@ -17,11 +17,172 @@ type Config struct {
 	Schedule        ScheduleConfig        `yaml:"schedule"`
 	MinimumDaysLeft float64               `yaml:"minimum_days_left"`
 	HistoryDir      string                `yaml:"history_dir"`
+	HistoryStore    HistoryStoreConfig    `yaml:"history_store"`
 	Telegram        TelegramConfig        `yaml:"telegram"`
+	Notifiers       []NotifierConfig      `yaml:"notifiers"`
+	Webserver       WebserverConfig       `yaml:"webserver"`
+	Backup          BackupConfig          `yaml:"backup"`
+	Anomaly         AnomalyConfig         `yaml:"anomaly"`
+	Alerts          AlertsConfig          `yaml:"alerts"`
+	API             APIConfig             `yaml:"api"`
+	FX              FXConfig              `yaml:"fx"`
+	Concurrency     ConcurrencyConfig     `yaml:"concurrency"`
 	Services        []ServiceConfig       `yaml:"services"`
 	StaticServices  []StaticServiceConfig `yaml:"static_services"`
 }
 
+// ConcurrencyConfig tunes how many services are fetched in parallel, the
+// per-host outgoing request rate, and when a service's circuit breaker trips.
+type ConcurrencyConfig struct {
+	Workers                    int     `yaml:"workers"`
+	PerHostRateLimit           float64 `yaml:"per_host_rate_limit"`
+	PerHostBurst               int     `yaml:"per_host_burst"`
+	BreakerFailureThreshold    int     `yaml:"breaker_failure_threshold"`
+	BreakerResetTimeoutSeconds int     `yaml:"breaker_reset_timeout_seconds"`
+}
+
+// APIConfig controls the optional read-only JSON API server.
+type APIConfig struct {
+	Enabled         bool   `yaml:"enabled"`
+	Addr            string `yaml:"addr"`
+	CacheTTLSeconds int    `yaml:"cache_ttl_seconds"`
+}
+
+// HistoryStoreConfig selects how history.Manager persists its records: the
+// default JSON-file-per-service layout, or a SQL-backed store.
+type HistoryStoreConfig struct {
+	Kind string                 `yaml:"kind"`
+	SQL  *HistoryStoreSQLConfig `yaml:"sql"`
+}
+
+// HistoryStoreSQLConfig configures the SQL-backed history store.
+type HistoryStoreSQLConfig struct {
+	Driver       string `yaml:"driver"`
+	DSN          string `yaml:"dsn"`
+	MaxOpenConns int    `yaml:"max_open_conns"`
+}
+
+// FXConfig enables multi-currency normalization: balances reported in a
+// currency other than ReportingCurrency are converted into it via the
+// selected Kind provider before being reported and stored.
+type FXConfig struct {
+	Enabled           bool            `yaml:"enabled"`
+	ReportingCurrency string          `yaml:"reporting_currency"`
+	Kind              string          `yaml:"kind"`
+	CacheTTLSeconds   int             `yaml:"cache_ttl_seconds"`
+	CachePath         string          `yaml:"cache_path"`
+	Static            *FXStaticConfig `yaml:"static"`
+	ECB               *FXECBConfig    `yaml:"ecb"`
+	HTTP              *FXHTTPConfig   `yaml:"http"`
+}
+
+// FXStaticConfig is a fixed "BASE/QUOTE" -> rate table.
+type FXStaticConfig struct {
+	Rates map[string]float64 `yaml:"rates"`
+}
+
+// FXECBConfig points at an ECB daily reference rates feed; the ECB's
+// published default is used when URL is empty.
+type FXECBConfig struct {
+	URL string `yaml:"url"`
+}
+
+// FXHTTPConfig configures a generic HTTP+JSON rate provider. "{base}" and
+// "{quote}" placeholders in Request.URL and its headers/query are
+// substituted with the requested currency pair before every call.
+type FXHTTPConfig struct {
+	Request  RequestConfig `yaml:"request"`
+	RatePath string        `yaml:"rate_path"`
+}
+
+// AnomalyConfig tunes the EWMA-based anomaly detector in history.Manager.
+type AnomalyConfig struct {
+	Alpha     float64 `yaml:"alpha"`
+	Threshold float64 `yaml:"threshold"`
+}
+
+// BackupConfig controls the optional periodic history snapshot job.
+type BackupConfig struct {
+	Enabled      bool                    `yaml:"enabled"`
+	Cron         string                  `yaml:"cron"`
+	Destination  BackupDestinationConfig `yaml:"destination"`
+	RetainDaily  int                     `yaml:"retain_daily"`
+	RetainWeekly int                     `yaml:"retain_weekly"`
+}
+
+// BackupDestinationConfig selects where snapshots are uploaded.
+type BackupDestinationConfig struct {
+	Kind   string              `yaml:"kind"`
+	Local  *BackupLocalConfig  `yaml:"local"`
+	S3     *BackupS3Config     `yaml:"s3"`
+	Rclone *BackupRcloneConfig `yaml:"rclone"`
+}
+
+// BackupLocalConfig stores snapshots in a local directory.
+type BackupLocalConfig struct {
+	Path string `yaml:"path"`
+}
+
+// BackupS3Config stores snapshots in an S3-compatible object store.
+type BackupS3Config struct {
+	Endpoint  string `yaml:"endpoint"`
+	Region    string `yaml:"region"`
+	Bucket    string `yaml:"bucket"`
+	Prefix    string `yaml:"prefix"`
+	AccessKey string `yaml:"access_key"`
+	SecretKey string `yaml:"secret_key"`
+}
+
+// BackupRcloneConfig stores snapshots via an rclone remote (shelling out to
+// the rclone binary already configured on the host).
+type BackupRcloneConfig struct {
+	Remote string `yaml:"remote"`
+}
+
+// NotifierConfig describes a single configured notification transport.
+// Exactly one of the type-specific blocks matching Type should be set.
+type NotifierConfig struct {
+	Type     string                 `yaml:"type"`
+	Telegram *TelegramConfig        `yaml:"telegram,omitempty"`
+	Discord  *DiscordNotifierConfig `yaml:"discord,omitempty"`
+	Slack    *SlackNotifierConfig   `yaml:"slack,omitempty"`
+	Email    *EmailNotifierConfig   `yaml:"email,omitempty"`
+	Webhook  *WebhookNotifierConfig `yaml:"webhook,omitempty"`
+}
+
+// DiscordNotifierConfig configures a Discord incoming webhook transport.
+type DiscordNotifierConfig struct {
+	WebhookURL string `yaml:"webhook_url"`
+}
+
+// SlackNotifierConfig configures a Slack incoming webhook transport.
+type SlackNotifierConfig struct {
+	WebhookURL string `yaml:"webhook_url"`
+}
+
+// EmailNotifierConfig configures an SMTP email transport.
+type EmailNotifierConfig struct {
+	Host     string   `yaml:"host"`
+	Port     int      `yaml:"port"`
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+}
+
+// WebhookNotifierConfig configures a generic JSON webhook transport, optionally HMAC-signed.
+type WebhookNotifierConfig struct {
+	URL          string `yaml:"url"`
+	Secret       string `yaml:"secret"`
+	SignatureHdr string `yaml:"signature_header"`
+}
+
+// WebserverConfig controls the optional built-in dashboard.
+type WebserverConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Addr    string `yaml:"addr"`
+}
+
 // ScheduleConfig keeps daily trigger settings.
 type ScheduleConfig struct {
 	Time     string `yaml:"time"`
@@ -36,32 +197,116 @@ type TelegramConfig struct {
 
 // ServiceConfig describes how to query and parse service balance.
 type ServiceConfig struct {
-	Name           string         `yaml:"name"`
-	HistoryFile    string         `yaml:"history_file"`
-	CurrencySymbol string         `yaml:"currency_symbol"`
-	BillingMode    string         `yaml:"billing_mode"`
-	Auth           *AuthConfig    `yaml:"auth"`
-	Request        RequestConfig  `yaml:"request"`
-	Response       ResponseConfig `yaml:"response"`
+	Name           string            `yaml:"name"`
+	HistoryFile    string            `yaml:"history_file"`
+	CurrencySymbol string            `yaml:"currency_symbol"`
+	BillingMode    string            `yaml:"billing_mode"`
+	Kind           string            `yaml:"kind"`
+	Auth           *AuthConfig       `yaml:"auth"`
+	Request        RequestConfig     `yaml:"request"`
+	Response       ResponseConfig    `yaml:"response"`
+	GraphQL        *GraphQLConfig    `yaml:"graphql"`
+	HTML           *HTMLConfig       `yaml:"html"`
+	SOAP           *SOAPConfig       `yaml:"soap"`
+	Budget         *BudgetConfig     `yaml:"budget"`
+	Alerts         []AlertRuleConfig `yaml:"alerts"`
+}
+
+// AlertRuleConfig configures one alerts.Engine rule for a service. Type must
+// be one of "balance_below", "spend_above_daily_avg_x", "currency_change", or
+// "fetch_error_streak"; Threshold's meaning depends on Type (a currency
+// amount, a multiplier of the daily average, unused, or a consecutive-error
+// count).
+type AlertRuleConfig struct {
+	Type      string  `yaml:"type"`
+	Threshold float64 `yaml:"threshold"`
+}
+
+// AlertsConfig controls the optional alerts.Engine and its sinks.
+type AlertsConfig struct {
+	Enabled      bool                `yaml:"enabled"`
+	PersistState bool                `yaml:"persist_state"`
+	UseNotifiers bool                `yaml:"use_notifiers"`
+	Webhook      *AlertWebhookConfig `yaml:"webhook"`
+}
+
+// AlertWebhookConfig configures the dedicated alert-delivery webhook sink.
+type AlertWebhookConfig struct {
+	URL          string `yaml:"url"`
+	Secret       string `yaml:"secret"`
+	SignatureHdr string `yaml:"signature_header"`
+	MaxRetries   int    `yaml:"max_retries"`
+}
+
+// GraphQLConfig configures the "graphql" fetcher kind.
+type GraphQLConfig struct {
+	Request   RequestConfig  `yaml:"request"`
+	Query     string         `yaml:"query"`
+	Variables map[string]any `yaml:"variables"`
+}
+
+// HTMLConfig configures the "html" fetcher kind: a CSS selector, with an
+// optional regular expression applied to the selected text before parsing.
+type HTMLConfig struct {
+	Request  RequestConfig `yaml:"request"`
+	Selector string        `yaml:"selector"`
+	Regex    string        `yaml:"regex"`
+}
+
+// SOAPConfig configures the "soap" fetcher kind: a templated XML body and
+// the XPath expression used to extract the balance from the response.
+type SOAPConfig struct {
+	Request      RequestConfig `yaml:"request"`
+	BodyTemplate string        `yaml:"body_template"`
+	XPath        string        `yaml:"xpath"`
 }
 
 // StaticServiceConfig describes a fixed monthly payment reminder.
 type StaticServiceConfig struct {
-	Name             string  `yaml:"name"`
-	CurrencySymbol   string  `yaml:"currency_symbol"`
-	Amount           float64 `yaml:"amount"`
-	BillingDay       int     `yaml:"billing_day"`
-	NotifyBeforeDays int     `yaml:"notify_before_days"`
-	URLPay           string  `yaml:"url_pay"`
-	CardPay          string  `yaml:"card_pay"`
+	Name             string        `yaml:"name"`
+	CurrencySymbol   string        `yaml:"currency_symbol"`
+	Amount           float64       `yaml:"amount"`
+	BillingDay       int           `yaml:"billing_day"`
+	NotifyBeforeDays int           `yaml:"notify_before_days"`
+	URLPay           string        `yaml:"url_pay"`
+	CardPay          string        `yaml:"card_pay"`
+	Budget           *BudgetConfig `yaml:"budget"`
 }
 
-// AuthConfig specifies optional pre-request authentication flow.
+// BudgetConfig caps monthly spend for a service and tunes when to warn
+// before the limit is actually reached.
+type BudgetConfig struct {
+	MonthlyLimit float64 `yaml:"monthly_limit"`
+	Currency     string  `yaml:"currency"`
+	ResetDay     int     `yaml:"reset_day"`
+	WarnPercent  float64 `yaml:"warn_percent"`
+}
+
+// AuthConfig specifies optional pre-request authentication flow. Type
+// selects between "token" (the default: fetch a token via Request and send
+// it as a header) and "signed" (HMAC-sign each outgoing request in place,
+// as exchange APIs like MAX/Binance/Kraken require).
 type AuthConfig struct {
-	Request   RequestConfig `yaml:"request"`
-	TokenPath string        `yaml:"token_path"`
-	Header    string        `yaml:"header"`
-	Prefix    string        `yaml:"prefix"`
+	Type      string            `yaml:"type"`
+	Request   RequestConfig     `yaml:"request"`
+	TokenPath string            `yaml:"token_path"`
+	Header    string            `yaml:"header"`
+	Prefix    string            `yaml:"prefix"`
+	Signed    *SignedAuthConfig `yaml:"signed"`
+}
+
+// SignedAuthConfig HMAC-signs each request from a canonical string built out
+// of SignedFields, so different exchanges' signing schemes can be matched
+// without a code change per service.
+type SignedAuthConfig struct {
+	APIKeyEnv       string   `yaml:"api_key_env"`
+	APISecretEnv    string   `yaml:"api_secret_env"`
+	Algorithm       string   `yaml:"algorithm"`
+	AccessKeyHeader string   `yaml:"access_key_header"`
+	SignatureHeader string   `yaml:"signature_header"`
+	TimestampHeader string   `yaml:"timestamp_header"`
+	SignedFields    []string `yaml:"signed_fields"`
+	Encoding        string   `yaml:"encoding"`
 }
 
 // RequestConfig holds HTTP request parameters.
@@ -74,12 +319,16 @@ type RequestConfig struct {
 	TimeoutSeconds int               `yaml:"timeout_seconds"`
 }
 
-// ResponseConfig defines how to extract balance values.
+// ResponseConfig defines how to extract balance values. SampleResponse is
+// optional raw JSON an operator can paste in so the config-validation panel
+// can check BalancePath resolves against it without waiting on a live probe
+// (see webserver.CheckSamplePath).
 type ResponseConfig struct {
-	BalancePath   string  `yaml:"balance_path"`
-	BalanceScale  float64 `yaml:"balance_scale"`
-	CurrencyField string  `yaml:"currency_field"`
-	Multiple      bool    `yaml:"multiple" default:"false"`
+	BalancePath    string  `yaml:"balance_path"`
+	BalanceScale   float64 `yaml:"balance_scale"`
+	CurrencyField  string  `yaml:"currency_field"`
+	Multiple       bool    `yaml:"multiple" default:"false"`
+	SampleResponse string  `yaml:"sample_response"`
 }
 
 // Load parses configuration file and applies defaults.
@@ -102,16 +351,67 @@ func Load(path string) (*Config, error) {
 		cfg.HistoryDir = "data"
 	}
 
+	if err := cfg.HistoryStore.applyDefaults(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.FX.applyDefaults(); err != nil {
+		return nil, err
+	}
+
+	cfg.Concurrency.applyDefaults()
+
+	if cfg.Anomaly.Alpha <= 0 {
+		cfg.Anomaly.Alpha = 0.3
+	}
+	if cfg.Anomaly.Threshold <= 0 {
+		cfg.Anomaly.Threshold = 3.0
+	}
+
+	if cfg.Webserver.Enabled && cfg.Webserver.Addr == "" {
+		cfg.Webserver.Addr = ":8080"
+	}
+
+	if cfg.API.Enabled {
+		if cfg.API.Addr == "" {
+			cfg.API.Addr = ":8081"
+		}
+		if cfg.API.CacheTTLSeconds <= 0 {
+			cfg.API.CacheTTLSeconds = 30
+		}
+	}
+
+	if cfg.Backup.Enabled {
+		if err := cfg.Backup.applyDefaults(); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Alerts.Enabled {
+		if err := cfg.Alerts.applyDefaults(); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := cfg.Schedule.validate(); err != nil {
 		return nil, err
 	}
 
-	if cfg.Telegram.Token == "" {
-		return nil, errors.New("telegram token is required")
+	if len(cfg.Notifiers) == 0 {
+		if cfg.Telegram.Token == "" {
+			return nil, errors.New("telegram token is required")
+		}
+		if len(cfg.Telegram.ChatIDs) == 0 {
+			return nil, errors.New("at least one telegram chat id is required")
+		}
+		// Legacy single-block config: treat it as the sole configured notifier.
+		cfg.Notifiers = []NotifierConfig{{Type: "telegram", Telegram: &cfg.Telegram}}
 	}
 
-	if len(cfg.Telegram.ChatIDs) == 0 {
-		return nil, errors.New("at least one telegram chat id is required")
+	for i := range cfg.Notifiers {
+		if err := cfg.Notifiers[i].validate(); err != nil {
+			return nil, err
+		}
 	}
 
 	if len(cfg.Services) == 0 && len(cfg.StaticServices) == 0 {
@@ -183,18 +483,55 @@ func (s *ServiceConfig) applyDefaults(historyDir string) error {
 		return fmt.Errorf("service %q: billing_mode must be prepaid or postpaid", s.Name)
 	}
 
-	if strings.TrimSpace(s.Request.URL) == "" {
-		return fmt.Errorf("service %q: request url is required", s.Name)
-	}
-
-	if strings.TrimSpace(s.Response.BalancePath) == "" {
-		return fmt.Errorf("service %q: response.balance_path is required", s.Name)
+	if strings.TrimSpace(s.Kind) == "" {
+		s.Kind = "json"
+	} else {
+		s.Kind = strings.ToLower(strings.TrimSpace(s.Kind))
 	}
 
 	if s.Response.BalanceScale == 0 {
 		s.Response.BalanceScale = 1
 	}
 
+	switch s.Kind {
+	case "json":
+		if strings.TrimSpace(s.Request.URL) == "" {
+			return fmt.Errorf("service %q: request url is required", s.Name)
+		}
+		if strings.TrimSpace(s.Response.BalancePath) == "" {
+			return fmt.Errorf("service %q: response.balance_path is required", s.Name)
+		}
+	case "graphql":
+		if err := s.GraphQL.validate(s.Name); err != nil {
+			return err
+		}
+		if strings.TrimSpace(s.Response.BalancePath) == "" {
+			return fmt.Errorf("service %q: response.balance_path is required", s.Name)
+		}
+	case "html":
+		if err := s.HTML.validate(s.Name); err != nil {
+			return err
+		}
+	case "soap":
+		if err := s.SOAP.validate(s.Name); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("service %q: kind must be json, graphql, html, or soap", s.Name)
+	}
+
+	if s.Budget != nil {
+		if err := s.Budget.validate(s.Name); err != nil {
+			return err
+		}
+	}
+
+	for i := range s.Alerts {
+		if err := s.Alerts[i].validate(s.Name); err != nil {
+			return err
+		}
+	}
+
 	if s.HistoryFile == "" {
 		s.HistoryFile = strings.ToLower(sanitizeFileName(s.Name)) + ".json"
 	}
@@ -243,5 +580,10 @@ func (s *StaticServiceConfig) validate() error {
 	if s.NotifyBeforeDays < 0 {
 		return fmt.Errorf("static service %q: notify_before_days must be >= 0", s.Name)
 	}
+	if s.Budget != nil {
+		if err := s.Budget.validate(s.Name); err != nil {
+			return err
+		}
+	}
 	return nil
 }
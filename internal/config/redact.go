@@ -0,0 +1,52 @@
+package config
+
+import "gopkg.in/yaml.v3"
+
+// Redact returns a YAML copy of cfg with secrets blanked out, safe to bundle
+// into backups or expose over the dashboard.
+func (c *Config) Redact() ([]byte, error) {
+	redacted := *c
+
+	redacted.Telegram.Token = "REDACTED"
+
+	redacted.Notifiers = make([]NotifierConfig, len(c.Notifiers))
+	copy(redacted.Notifiers, c.Notifiers)
+	for i := range redacted.Notifiers {
+		n := &redacted.Notifiers[i]
+		if n.Telegram != nil {
+			token := *n.Telegram
+			token.Token = "REDACTED"
+			n.Telegram = &token
+		}
+		if n.Email != nil {
+			email := *n.Email
+			email.Password = "REDACTED"
+			n.Email = &email
+		}
+		if n.Webhook != nil {
+			webhook := *n.Webhook
+			webhook.Secret = "REDACTED"
+			n.Webhook = &webhook
+		}
+	}
+
+	if redacted.HistoryStore.SQL != nil {
+		sql := *redacted.HistoryStore.SQL
+		sql.DSN = "REDACTED"
+		redacted.HistoryStore.SQL = &sql
+	}
+
+	if redacted.Backup.Destination.S3 != nil {
+		s3 := *redacted.Backup.Destination.S3
+		s3.SecretKey = "REDACTED"
+		redacted.Backup.Destination.S3 = &s3
+	}
+
+	if redacted.Alerts.Webhook != nil {
+		webhook := *redacted.Alerts.Webhook
+		webhook.Secret = "REDACTED"
+		redacted.Alerts.Webhook = &webhook
+	}
+
+	return yaml.Marshal(redacted)
+}
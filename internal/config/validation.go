@@ -22,18 +22,300 @@ func (s *ScheduleConfig) validate() error {
 	return nil
 }
 
+func (n *NotifierConfig) validate() error {
+	switch strings.ToLower(strings.TrimSpace(n.Type)) {
+	case "telegram":
+		if n.Telegram == nil || n.Telegram.Token == "" {
+			return errors.New("notifier telegram: token is required")
+		}
+		if len(n.Telegram.ChatIDs) == 0 {
+			return errors.New("notifier telegram: at least one chat id is required")
+		}
+	case "discord":
+		if n.Discord == nil || strings.TrimSpace(n.Discord.WebhookURL) == "" {
+			return errors.New("notifier discord: webhook_url is required")
+		}
+	case "slack":
+		if n.Slack == nil || strings.TrimSpace(n.Slack.WebhookURL) == "" {
+			return errors.New("notifier slack: webhook_url is required")
+		}
+	case "email":
+		if n.Email == nil || strings.TrimSpace(n.Email.Host) == "" {
+			return errors.New("notifier email: host is required")
+		}
+		if len(n.Email.To) == 0 {
+			return errors.New("notifier email: at least one recipient is required")
+		}
+	case "webhook":
+		if n.Webhook == nil || strings.TrimSpace(n.Webhook.URL) == "" {
+			return errors.New("notifier webhook: url is required")
+		}
+	default:
+		return fmt.Errorf("notifier: unknown type %q", n.Type)
+	}
+	return nil
+}
+
+func (b *BudgetConfig) validate(serviceName string) error {
+	if b.MonthlyLimit <= 0 {
+		return fmt.Errorf("service %q: budget.monthly_limit must be positive", serviceName)
+	}
+	if b.ResetDay < 0 || b.ResetDay > 31 {
+		return fmt.Errorf("service %q: budget.reset_day must be between 0 and 31", serviceName)
+	}
+	if b.WarnPercent < 0 || b.WarnPercent > 100 {
+		return fmt.Errorf("service %q: budget.warn_percent must be between 0 and 100", serviceName)
+	}
+	return nil
+}
+
+func (b *BackupConfig) applyDefaults() error {
+	if strings.TrimSpace(b.Cron) == "" {
+		b.Cron = "0 0 3 * * *"
+	}
+	if b.RetainDaily <= 0 {
+		b.RetainDaily = 7
+	}
+	if b.RetainWeekly <= 0 {
+		b.RetainWeekly = 4
+	}
+
+	switch strings.ToLower(strings.TrimSpace(b.Destination.Kind)) {
+	case "local":
+		if b.Destination.Local == nil || strings.TrimSpace(b.Destination.Local.Path) == "" {
+			return errors.New("backup.destination.local.path is required")
+		}
+	case "s3":
+		if b.Destination.S3 == nil || strings.TrimSpace(b.Destination.S3.Bucket) == "" {
+			return errors.New("backup.destination.s3.bucket is required")
+		}
+	case "rclone":
+		if b.Destination.Rclone == nil || strings.TrimSpace(b.Destination.Rclone.Remote) == "" {
+			return errors.New("backup.destination.rclone.remote is required")
+		}
+	default:
+		return fmt.Errorf("backup.destination.kind must be local, s3, or rclone, got %q", b.Destination.Kind)
+	}
+	return nil
+}
+
+func (f *FXConfig) applyDefaults() error {
+	if !f.Enabled {
+		return nil
+	}
+
+	if strings.TrimSpace(f.ReportingCurrency) == "" {
+		return errors.New("fx.reporting_currency is required when fx is enabled")
+	}
+	if f.CacheTTLSeconds <= 0 {
+		f.CacheTTLSeconds = 3600
+	}
+	if strings.TrimSpace(f.CachePath) == "" {
+		f.CachePath = "data/fx_rates.json"
+	}
+
+	switch strings.ToLower(strings.TrimSpace(f.Kind)) {
+	case "static":
+		if f.Static == nil || len(f.Static.Rates) == 0 {
+			return errors.New("fx.static.rates must have at least one entry")
+		}
+	case "ecb":
+	case "http":
+		if f.HTTP == nil || strings.TrimSpace(f.HTTP.Request.URL) == "" {
+			return errors.New("fx.http.request.url is required")
+		}
+		if strings.TrimSpace(f.HTTP.RatePath) == "" {
+			return errors.New("fx.http.rate_path is required")
+		}
+	default:
+		return fmt.Errorf("fx.kind must be static, ecb, or http, got %q", f.Kind)
+	}
+	return nil
+}
+
+func (c *ConcurrencyConfig) applyDefaults() {
+	if c.Workers < 1 {
+		c.Workers = 4
+	}
+	if c.PerHostRateLimit < 0 {
+		c.PerHostRateLimit = 0
+	}
+	if c.PerHostBurst < 1 {
+		c.PerHostBurst = 1
+	}
+	if c.BreakerFailureThreshold < 1 {
+		c.BreakerFailureThreshold = 3
+	}
+	if c.BreakerResetTimeoutSeconds < 1 {
+		c.BreakerResetTimeoutSeconds = 60
+	}
+}
+
+func (h *HistoryStoreConfig) applyDefaults() error {
+	switch strings.ToLower(strings.TrimSpace(h.Kind)) {
+	case "", "file":
+		h.Kind = "file"
+	case "sql":
+		if h.SQL == nil || strings.TrimSpace(h.SQL.Driver) == "" {
+			return errors.New("history_store.sql.driver is required")
+		}
+		switch strings.ToLower(strings.TrimSpace(h.SQL.Driver)) {
+		case "sqlite", "postgres":
+		default:
+			return fmt.Errorf("history_store.sql.driver must be sqlite or postgres, got %q", h.SQL.Driver)
+		}
+		if strings.TrimSpace(h.SQL.DSN) == "" {
+			return errors.New("history_store.sql.dsn is required")
+		}
+		if h.SQL.MaxOpenConns <= 0 {
+			h.SQL.MaxOpenConns = 10
+		}
+	default:
+		return fmt.Errorf("history_store.kind must be file or sql, got %q", h.Kind)
+	}
+	return nil
+}
+
+func (a *AlertRuleConfig) validate(serviceName string) error {
+	switch strings.ToLower(strings.TrimSpace(a.Type)) {
+	case "balance_below", "spend_above_daily_avg_x", "fetch_error_streak":
+		if a.Threshold <= 0 {
+			return fmt.Errorf("service %q: alert %q: threshold must be positive", serviceName, a.Type)
+		}
+	case "currency_change":
+	default:
+		return fmt.Errorf("service %q: alert type must be balance_below, spend_above_daily_avg_x, currency_change, or fetch_error_streak, got %q", serviceName, a.Type)
+	}
+	return nil
+}
+
+func (a *AlertsConfig) applyDefaults() error {
+	if a.Webhook != nil {
+		if strings.TrimSpace(a.Webhook.URL) == "" {
+			return errors.New("alerts.webhook.url is required")
+		}
+		if strings.TrimSpace(a.Webhook.SignatureHdr) == "" {
+			a.Webhook.SignatureHdr = "X-FundsPulse-Alert-Signature"
+		}
+		if a.Webhook.MaxRetries <= 0 {
+			a.Webhook.MaxRetries = 3
+		}
+	}
+
+	if a.Webhook == nil && !a.UseNotifiers {
+		return errors.New("alerts: at least one of webhook or use_notifiers must be configured")
+	}
+	return nil
+}
+
+func (g *GraphQLConfig) validate(serviceName string) error {
+	if g == nil {
+		return fmt.Errorf("service %q: graphql config is required for kind graphql", serviceName)
+	}
+	if strings.TrimSpace(g.Request.URL) == "" {
+		return fmt.Errorf("service %q: graphql.request.url is required", serviceName)
+	}
+	if strings.TrimSpace(g.Query) == "" {
+		return fmt.Errorf("service %q: graphql.query is required", serviceName)
+	}
+	return nil
+}
+
+func (h *HTMLConfig) validate(serviceName string) error {
+	if h == nil {
+		return fmt.Errorf("service %q: html config is required for kind html", serviceName)
+	}
+	if strings.TrimSpace(h.Request.URL) == "" {
+		return fmt.Errorf("service %q: html.request.url is required", serviceName)
+	}
+	if strings.TrimSpace(h.Selector) == "" {
+		return fmt.Errorf("service %q: html.selector is required", serviceName)
+	}
+	return nil
+}
+
+func (s *SOAPConfig) validate(serviceName string) error {
+	if s == nil {
+		return fmt.Errorf("service %q: soap config is required for kind soap", serviceName)
+	}
+	if strings.TrimSpace(s.Request.URL) == "" {
+		return fmt.Errorf("service %q: soap.request.url is required", serviceName)
+	}
+	if strings.TrimSpace(s.BodyTemplate) == "" {
+		return fmt.Errorf("service %q: soap.body_template is required", serviceName)
+	}
+	if strings.TrimSpace(s.XPath) == "" {
+		return fmt.Errorf("service %q: soap.xpath is required", serviceName)
+	}
+	return nil
+}
+
 func (a *AuthConfig) validate(serviceName string) error {
-	if strings.TrimSpace(a.TokenPath) == "" {
-		return fmt.Errorf("service %q: auth.token_path is required", serviceName)
+	authType := strings.ToLower(strings.TrimSpace(a.Type))
+	if authType == "" {
+		authType = "token"
+		a.Type = authType
+	}
+
+	switch authType {
+	case "token":
+		if strings.TrimSpace(a.TokenPath) == "" {
+			return fmt.Errorf("service %q: auth.token_path is required", serviceName)
+		}
+		if strings.TrimSpace(a.Header) == "" {
+			return fmt.Errorf("service %q: auth.header is required", serviceName)
+		}
+		if strings.TrimSpace(a.Request.URL) == "" {
+			return fmt.Errorf("service %q: auth.request.url is required", serviceName)
+		}
+		if a.Request.Method == "" {
+			a.Request.Method = "POST"
+		}
+	case "signed":
+		if err := a.Signed.applyDefaults(serviceName); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("service %q: auth.type must be token or signed, got %q", serviceName, a.Type)
+	}
+	return nil
+}
+
+func (s *SignedAuthConfig) applyDefaults(serviceName string) error {
+	if s == nil {
+		return fmt.Errorf("service %q: auth.signed config is required for auth type signed", serviceName)
+	}
+	if strings.TrimSpace(s.APIKeyEnv) == "" {
+		return fmt.Errorf("service %q: auth.signed.api_key_env is required", serviceName)
 	}
-	if strings.TrimSpace(a.Header) == "" {
-		return fmt.Errorf("service %q: auth.header is required", serviceName)
+	if strings.TrimSpace(s.APISecretEnv) == "" {
+		return fmt.Errorf("service %q: auth.signed.api_secret_env is required", serviceName)
 	}
-	if strings.TrimSpace(a.Request.URL) == "" {
-		return fmt.Errorf("service %q: auth.request.url is required", serviceName)
+	if len(s.SignedFields) == 0 {
+		return fmt.Errorf("service %q: auth.signed.signed_fields must list at least one field", serviceName)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(s.Algorithm)) {
+	case "":
+		s.Algorithm = "hmac-sha256"
+	case "hmac-sha256", "hmac-sha512":
+	default:
+		return fmt.Errorf("service %q: auth.signed.algorithm must be hmac-sha256 or hmac-sha512, got %q", serviceName, s.Algorithm)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(s.Encoding)) {
+	case "":
+		s.Encoding = "hex"
+	case "hex", "base64":
+	default:
+		return fmt.Errorf("service %q: auth.signed.encoding must be hex or base64, got %q", serviceName, s.Encoding)
+	}
+
+	if strings.TrimSpace(s.SignatureHeader) == "" {
+		return fmt.Errorf("service %q: auth.signed.signature_header is required", serviceName)
 	}
-	if a.Request.Method == "" {
-		a.Request.Method = "POST"
+	if s.TimestampHeader == "" {
+		s.TimestampHeader = "X-Timestamp"
 	}
 	return nil
 }
@@ -0,0 +1,42 @@
+package fx
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sarff/FundsPulse/internal/config"
+)
+
+// Build constructs the Rates selected by cfg.Kind ("static", "ecb", or
+// "http"). It returns a nil Rates (and a nil error) when fx is disabled, so
+// callers can pass the result straight through without a type switch.
+func Build(cfg config.FXConfig) (Rates, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	cache := NewCache(cfg.CachePath, time.Duration(cfg.CacheTTLSeconds)*time.Second)
+
+	switch strings.ToLower(strings.TrimSpace(cfg.Kind)) {
+	case "static":
+		if cfg.Static == nil {
+			return nil, fmt.Errorf("fx: static config is required for kind static")
+		}
+		return NewStaticRates(cfg.Static.Rates), nil
+	case "ecb":
+		url := ""
+		if cfg.ECB != nil {
+			url = cfg.ECB.URL
+		}
+		return NewECBRates(url, cache), nil
+	case "http":
+		if cfg.HTTP == nil {
+			return nil, fmt.Errorf("fx: http config is required for kind http")
+		}
+		return NewHTTPRates(cfg.HTTP.Request.URL, cfg.HTTP.Request.Headers, cfg.HTTP.Request.Query,
+			cfg.HTTP.RatePath, cfg.HTTP.Request.TimeoutSeconds, cache), nil
+	default:
+		return nil, fmt.Errorf("fx: unknown kind %q", cfg.Kind)
+	}
+}
@@ -0,0 +1,108 @@
+package fx
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheEntry is one cached base/quote rate.
+type cacheEntry struct {
+	Rate      float64   `json:"rate"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// Cache persists fetched rates to a JSON file on disk with a TTL. A rate
+// past its TTL is still returned on request (Get reports it as stale rather
+// than refusing it), so a provider can fall back to the last known rate
+// instead of failing outright when a live refresh errors.
+type Cache struct {
+	mu      sync.Mutex
+	path    string
+	ttl     time.Duration
+	entries map[string]cacheEntry
+	loaded  bool
+}
+
+// NewCache builds a disk-backed cache rooted at path, valid for ttl. path
+// may be empty, in which case the cache lives in memory only.
+func NewCache(path string, ttl time.Duration) *Cache {
+	return &Cache{path: path, ttl: ttl, entries: make(map[string]cacheEntry)}
+}
+
+func pairKey(base, quote string) string {
+	return strings.ToUpper(base) + "/" + strings.ToUpper(quote)
+}
+
+func (c *Cache) ensureLoaded() {
+	if c.loaded {
+		return
+	}
+	c.loaded = true
+	if c.path == "" {
+		return
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return
+	}
+
+	var entries map[string]cacheEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return
+	}
+	c.entries = entries
+}
+
+// Get returns the cached rate for base/quote, whether it is still within
+// ttl, and whether an entry exists at all.
+func (c *Cache) Get(base, quote string) (rate float64, fresh bool, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ensureLoaded()
+
+	entry, found := c.entries[pairKey(base, quote)]
+	if !found {
+		return 0, false, false
+	}
+	return entry.Rate, time.Since(entry.FetchedAt) < c.ttl, true
+}
+
+// Set stores rate for base/quote at fetchedAt and persists the cache to disk.
+func (c *Cache) Set(base, quote string, rate float64, fetchedAt time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ensureLoaded()
+
+	c.entries[pairKey(base, quote)] = cacheEntry{Rate: rate, FetchedAt: fetchedAt}
+	return c.persist()
+}
+
+func (c *Cache) persist() error {
+	if c.path == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("create fx cache dir: %v", err)
+	}
+
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode fx cache: %v", err)
+	}
+
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write fx cache tmp: %v", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("replace fx cache: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package fx
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// defaultECBURL is the European Central Bank's published daily reference
+// rates feed.
+const defaultECBURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+
+// ecbEnvelope models the subset of the ECB feed's XML we need: a flat list
+// of (currency, rate) pairs quoted against EUR.
+type ecbEnvelope struct {
+	Cube struct {
+		Cube struct {
+			Rates []struct {
+				Currency string  `xml:"currency,attr"`
+				Rate     float64 `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+// ECBRates serves the ECB's daily EUR reference rates, refreshing the whole
+// feed through cache so a scheduled run doesn't hit it once per service.
+type ECBRates struct {
+	http  *resty.Client
+	url   string
+	cache *Cache
+}
+
+// NewECBRates builds an ECBRates fetching url (defaultECBURL when empty),
+// backed by cache.
+func NewECBRates(url string, cache *Cache) *ECBRates {
+	if strings.TrimSpace(url) == "" {
+		url = defaultECBURL
+	}
+
+	http := resty.New()
+	http.SetTimeout(15 * time.Second)
+
+	return &ECBRates{http: http, url: url, cache: cache}
+}
+
+// Rate derives base/quote from the EUR-quoted feed: (EUR/quote)/(EUR/base).
+func (e *ECBRates) Rate(ctx context.Context, base, quote string, _ time.Time) (float64, error) {
+	base, quote = strings.ToUpper(base), strings.ToUpper(quote)
+	if base == quote {
+		return 1, nil
+	}
+
+	eurToBase, err := e.eurRate(ctx, base)
+	if err != nil {
+		return 0, err
+	}
+	eurToQuote, err := e.eurRate(ctx, quote)
+	if err != nil {
+		return 0, err
+	}
+
+	if base == "EUR" {
+		return eurToQuote, nil
+	}
+	if eurToBase == 0 {
+		return 0, fmt.Errorf("fx: ecb rate for %s is zero", base)
+	}
+	if quote == "EUR" {
+		return 1 / eurToBase, nil
+	}
+	return eurToQuote / eurToBase, nil
+}
+
+// eurRate returns how many units of currency one EUR buys, refreshing (and
+// caching every currency in) the feed on a miss or stale entry, and falling
+// back to the last cached value when the refresh itself fails.
+func (e *ECBRates) eurRate(ctx context.Context, currency string) (float64, error) {
+	if currency == "EUR" {
+		return 1, nil
+	}
+
+	if rate, fresh, ok := e.cache.Get("EUR", currency); ok && fresh {
+		return rate, nil
+	}
+
+	if err := e.refresh(ctx); err != nil {
+		if rate, _, ok := e.cache.Get("EUR", currency); ok {
+			return rate, nil
+		}
+		return 0, fmt.Errorf("fx: fetch ecb rates: %v", err)
+	}
+
+	if rate, _, ok := e.cache.Get("EUR", currency); ok {
+		return rate, nil
+	}
+	return 0, fmt.Errorf("fx: ecb feed has no rate for %s", currency)
+}
+
+func (e *ECBRates) refresh(ctx context.Context) error {
+	resp, err := e.http.R().SetContext(ctx).Get(e.url)
+	if err != nil {
+		return err
+	}
+	if !resp.IsSuccess() {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode())
+	}
+
+	var envelope ecbEnvelope
+	if err := xml.Unmarshal(resp.Body(), &envelope); err != nil {
+		return fmt.Errorf("parse ecb feed: %v", err)
+	}
+
+	now := time.Now()
+	for _, rate := range envelope.Cube.Cube.Rates {
+		if err := e.cache.Set("EUR", rate.Currency, rate.Rate, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+// Package fx converts amounts between currencies so balances fetched in
+// their native currency can be aggregated into one reporting currency.
+package fx
+
+import (
+	"context"
+	"time"
+)
+
+// Rates converts amounts between currencies. Rate reports how many units of
+// quote one unit of base is worth at (or nearest to) at.
+type Rates interface {
+	Rate(ctx context.Context, base, quote string, at time.Time) (float64, error)
+}
+
+// Convert applies rates to amount, converting it from base into quote.
+func Convert(ctx context.Context, rates Rates, amount float64, base, quote string, at time.Time) (float64, error) {
+	rate, err := rates.Rate(ctx, base, quote, at)
+	if err != nil {
+		return 0, err
+	}
+	return amount * rate, nil
+}
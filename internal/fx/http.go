@@ -0,0 +1,92 @@
+package fx
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/sarff/gjson"
+)
+
+// HTTPRates queries a generic HTTP+JSON rate endpoint, configured much like
+// service.Client's fetchers: a templated request plus a gjson path into the
+// response. "{base}" and "{quote}" in the URL, headers, and query values are
+// substituted with the requested currency pair before every call.
+type HTTPRates struct {
+	http     *resty.Client
+	url      string
+	headers  map[string]string
+	query    map[string]string
+	ratePath string
+	cache    *Cache
+}
+
+// NewHTTPRates builds an HTTPRates calling url and extracting ratePath from
+// the JSON response.
+func NewHTTPRates(url string, headers, query map[string]string, ratePath string, timeoutSeconds int, cache *Cache) *HTTPRates {
+	timeout := 15 * time.Second
+	if timeoutSeconds > 0 {
+		timeout = time.Duration(timeoutSeconds) * time.Second
+	}
+
+	http := resty.New()
+	http.SetTimeout(timeout)
+
+	return &HTTPRates{http: http, url: url, headers: headers, query: query, ratePath: ratePath, cache: cache}
+}
+
+func (h *HTTPRates) Rate(ctx context.Context, base, quote string, at time.Time) (float64, error) {
+	base, quote = strings.ToUpper(base), strings.ToUpper(quote)
+	if base == quote {
+		return 1, nil
+	}
+
+	if rate, fresh, ok := h.cache.Get(base, quote); ok && fresh {
+		return rate, nil
+	}
+
+	rate, err := h.fetch(ctx, base, quote)
+	if err != nil {
+		if cached, _, ok := h.cache.Get(base, quote); ok {
+			return cached, nil
+		}
+		return 0, err
+	}
+
+	if err := h.cache.Set(base, quote, rate, at); err != nil {
+		return 0, err
+	}
+	return rate, nil
+}
+
+func (h *HTTPRates) fetch(ctx context.Context, base, quote string) (float64, error) {
+	req := h.http.R().SetContext(ctx)
+
+	for key, value := range h.headers {
+		req.SetHeader(key, os.ExpandEnv(expandPair(value, base, quote)))
+	}
+	for key, value := range h.query {
+		req.SetQueryParam(key, os.ExpandEnv(expandPair(value, base, quote)))
+	}
+
+	resp, err := req.Get(expandPair(h.url, base, quote))
+	if err != nil {
+		return 0, fmt.Errorf("fx http request: %v", err)
+	}
+	if !resp.IsSuccess() {
+		return 0, fmt.Errorf("fx http request: unexpected status %d", resp.StatusCode())
+	}
+
+	rateValue := gjson.GetBytes(resp.Body(), h.ratePath)
+	if !rateValue.Exists() {
+		return 0, fmt.Errorf("fx http request: rate path %q not found", h.ratePath)
+	}
+	return rateValue.Float(), nil
+}
+
+func expandPair(template, base, quote string) string {
+	return strings.NewReplacer("{base}", base, "{quote}", quote).Replace(template)
+}
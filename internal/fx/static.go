@@ -0,0 +1,41 @@
+package fx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StaticRates serves a fixed table of "BASE/QUOTE" -> rate pairs from
+// config, for currencies that rarely move (or as a dependency-free
+// fallback) without reaching out to an external rate source.
+type StaticRates struct {
+	table map[string]float64
+}
+
+// NewStaticRates builds a Rates backed by table (keys "BASE/QUOTE", e.g.
+// "BTC/USD").
+func NewStaticRates(table map[string]float64) *StaticRates {
+	normalized := make(map[string]float64, len(table))
+	for pair, rate := range table {
+		normalized[strings.ToUpper(strings.TrimSpace(pair))] = rate
+	}
+	return &StaticRates{table: normalized}
+}
+
+// Rate looks up base/quote directly, then tries the inverse pair.
+func (s *StaticRates) Rate(_ context.Context, base, quote string, _ time.Time) (float64, error) {
+	base, quote = strings.ToUpper(base), strings.ToUpper(quote)
+	if base == quote {
+		return 1, nil
+	}
+
+	if rate, ok := s.table[base+"/"+quote]; ok {
+		return rate, nil
+	}
+	if rate, ok := s.table[quote+"/"+base]; ok && rate != 0 {
+		return 1 / rate, nil
+	}
+	return 0, fmt.Errorf("fx: no static rate for %s/%s", base, quote)
+}
@@ -0,0 +1,25 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sarff/FundsPulse/internal/config"
+)
+
+// BuildStore constructs the Store selected by cfg.Kind: "file" (the
+// default, one JSON file per service) or "sql" (SQLite/Postgres via
+// database/sql).
+func BuildStore(cfg config.HistoryStoreConfig) (Store, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.Kind)) {
+	case "", "file":
+		return NewJSONFileStore(), nil
+	case "sql":
+		if cfg.SQL == nil {
+			return nil, fmt.Errorf("history: store kind sql requires sql config")
+		}
+		return OpenSQLStore(cfg.SQL.Driver, cfg.SQL.DSN, cfg.SQL.MaxOpenConns)
+	default:
+		return nil, fmt.Errorf("history: unknown store kind %q", cfg.Kind)
+	}
+}
@@ -1,136 +1,291 @@
 package history
 
 import (
-	"encoding/json"
-	"fmt"
-	"os"
-	"path/filepath"
+	"math"
 	"time"
 )
 
-// Manager persists balance history and calculates daily averages.
+// robustScale converts mean absolute deviation into an estimate comparable to
+// a standard deviation, matching the usual MAD-based robust z-score.
+const robustScale = 1.4826
+
+// epsilon guards the z-score denominator against a zero deviation.
+const epsilon = 1e-9
+
+// Manager persists balance history and calculates daily averages. Actual
+// persistence is delegated to a Store, so the on-disk JSON layout and a
+// SQL-backed layout can be swapped without touching the EWMA/anomaly logic.
 type Manager struct {
-	days int
+	store     Store
+	days      int
+	alpha     float64
+	threshold float64
 }
 
-// DailySpend captures spend per day.
+// DailySpend captures spend per day, both in the (possibly fx-converted)
+// reporting currency and in the balance's native currency.
 type DailySpend struct {
-	Date   string  `json:"date"`
-	Amount float64 `json:"amount"`
+	Date           string  `json:"date"`
+	Amount         float64 `json:"amount"`
+	NativeAmount   float64 `json:"native_amount,omitempty"`
+	NativeCurrency string  `json:"native_currency,omitempty"`
 }
 
 // Record describes history file contents.
 type Record struct {
-	LastBalance float64      `json:"last_balance"`
-	LastUpdated string       `json:"last_updated"`
-	DailySpends []DailySpend `json:"daily_spends"`
+	LastBalance       float64      `json:"last_balance"`
+	LastNativeBalance float64      `json:"last_native_balance,omitempty"`
+	LastUpdated       string       `json:"last_updated"`
+	DailySpends       []DailySpend `json:"daily_spends"`
+	MonthKey          string       `json:"month_key"` // see BillingCycleKey
+	MonthStartBalance float64      `json:"month_start_balance"`
+	MonthToDateSpend  float64      `json:"month_to_date_spend"`
+	EWMAMean          float64      `json:"ewma_mean"`
+	EWMADev           float64      `json:"ewma_dev"`
+	EWMACount         int          `json:"ewma_count"`
+	WarmupDeltas      []float64    `json:"warmup_deltas,omitempty"`
 }
 
 // Result collects fresh spend and average information.
 type Result struct {
-	Spend   float64
-	Average float64
+	Spend        float64
+	NativeSpend  float64
+	Average      float64
+	MTDSpend     float64
+	Delta        float64
+	ZScore       float64
+	ExpectedLow  float64
+	ExpectedHigh float64
+	AnomalyWarn  bool
 }
 
-// NewManager builds history manager for configured window.
-func NewManager(days int) *Manager {
+// NewManager builds a history manager backed by store for configured window,
+// EWMA smoothing factor alpha and anomaly z-score threshold.
+func NewManager(store Store, days int, alpha, threshold float64) *Manager {
+	if store == nil {
+		store = NewJSONFileStore()
+	}
 	if days < 1 {
 		days = 1
 	}
-	return &Manager{days: days}
+	if alpha <= 0 {
+		alpha = 0.3
+	}
+	if threshold <= 0 {
+		threshold = 3.0
+	}
+	return &Manager{store: store, days: days, alpha: alpha, threshold: threshold}
 }
 
 // Update consumes fresh balance, refreshes history, and returns spend stats.
-func (m *Manager) Update(path string, balance float64, now time.Time) (Result, error) {
-	history, err := m.load(path)
+// balance is in the reporting currency (fx-converted if configured);
+// nativeBalance/nativeCurrency are the as-fetched values, stored alongside
+// it so spend remains inspectable in its original currency. billingMode
+// controls how month-to-date spend is accumulated: for "postpaid" services,
+// each day's balance increase is summed; for every other mode (prepaid), it
+// is the delta between the start-of-month balance and balance. resetDay
+// anchors that accumulation window to a billing cycle starting on that
+// day-of-month instead of the calendar month; 0 keeps the calendar month.
+func (m *Manager) Update(key string, balance, nativeBalance float64, nativeCurrency string, now time.Time, billingMode string, resetDay int) (Result, error) {
+	history, err := m.store.Load(key)
 	if err != nil {
 		return Result{}, err
 	}
 
 	spend := computeSpend(history.LastBalance, balance)
+	nativeSpend := computeSpend(history.LastNativeBalance, nativeBalance)
 	dayKey := now.Format("2006-01-02")
 
 	if len(history.DailySpends) == 0 || history.DailySpends[len(history.DailySpends)-1].Date != dayKey {
-		history.DailySpends = append(history.DailySpends, DailySpend{Date: dayKey, Amount: spend})
+		history.DailySpends = append(history.DailySpends, DailySpend{
+			Date: dayKey, Amount: spend, NativeAmount: nativeSpend, NativeCurrency: nativeCurrency,
+		})
 	} else {
-		history.DailySpends[len(history.DailySpends)-1].Amount = spend
+		last := &history.DailySpends[len(history.DailySpends)-1]
+		last.Amount = spend
+		last.NativeAmount = nativeSpend
+		last.NativeCurrency = nativeCurrency
 	}
 
 	if len(history.DailySpends) > m.days {
 		history.DailySpends = history.DailySpends[len(history.DailySpends)-m.days:]
 	}
 
+	cycleKey := BillingCycleKey(now, resetDay)
+	if history.MonthKey != cycleKey {
+		history.MonthKey = cycleKey
+		history.MonthStartBalance = history.LastBalance
+		history.MonthToDateSpend = 0
+	}
+
+	if billingMode == "postpaid" {
+		if charge := balance - history.LastBalance; charge > 0 {
+			history.MonthToDateSpend += charge
+		}
+	} else {
+		history.MonthToDateSpend = history.MonthStartBalance - balance
+		if history.MonthToDateSpend < 0 {
+			history.MonthToDateSpend = 0
+		}
+	}
+
+	delta := history.LastBalance - balance
+	zScore, expectedLow, expectedHigh, anomalyWarn := m.updateAnomalyStats(&history, delta, billingMode)
+
 	history.LastBalance = balance
+	history.LastNativeBalance = nativeBalance
 	history.LastUpdated = now.Format(time.RFC3339)
 
-	if err := m.save(path, history); err != nil {
+	if err := m.store.Save(key, history); err != nil {
 		return Result{}, err
 	}
 
-	return Result{Spend: spend, Average: average(history.DailySpends)}, nil
+	return Result{
+		Spend:        spend,
+		NativeSpend:  nativeSpend,
+		Average:      history.EWMAMean,
+		MTDSpend:     history.MonthToDateSpend,
+		Delta:        delta,
+		ZScore:       zScore,
+		ExpectedLow:  expectedLow,
+		ExpectedHigh: expectedHigh,
+		AnomalyWarn:  anomalyWarn,
+	}, nil
 }
 
-func (m *Manager) load(path string) (Record, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return Record{}, nil
+// updateAnomalyStats folds delta into history's EWMA mean/deviation (warming
+// up with a plain mean/MAD over the first m.days samples), then scores delta
+// against the resulting model. For prepaid services, a delta that flips sign
+// against an established spend trend (an unexpected top-up or refund) also
+// counts as an anomaly.
+func (m *Manager) updateAnomalyStats(history *Record, delta float64, billingMode string) (zScore, expectedLow, expectedHigh float64, warn bool) {
+	wasSpending := history.EWMACount > 0 && history.EWMAMean > 0
+
+	if history.EWMACount < m.days {
+		history.WarmupDeltas = append(history.WarmupDeltas, delta)
+		history.EWMACount++
+		history.EWMAMean = plainMean(history.WarmupDeltas)
+		history.EWMADev = plainMAD(history.WarmupDeltas, history.EWMAMean)
+		if history.EWMACount >= m.days {
+			history.WarmupDeltas = nil
 		}
-		return Record{}, fmt.Errorf("open history %q: %v", path, err)
+	} else {
+		history.EWMAMean = m.alpha*delta + (1-m.alpha)*history.EWMAMean
+		history.EWMADev = m.alpha*math.Abs(delta-history.EWMAMean) + (1-m.alpha)*history.EWMADev
+	}
+
+	zScore = (delta - history.EWMAMean) / (robustScale*history.EWMADev + epsilon)
+	expectedLow = history.EWMAMean - history.EWMADev
+	expectedHigh = history.EWMAMean + history.EWMADev
+
+	signFlip := billingMode != "postpaid" && wasSpending && delta < 0
+	warn = math.Abs(zScore) > m.threshold || signFlip
+	return zScore, expectedLow, expectedHigh, warn
+}
+
+// Load reads the persisted record for key without mutating it. It is meant
+// for read-only consumers (e.g. the api package) that report on history
+// without driving Update's spend/anomaly bookkeeping.
+func (m *Manager) Load(key string) (Record, error) {
+	return m.store.Load(key)
+}
+
+// Query reports key's daily spends between from and to, unconstrained by
+// the fixed averaging window Update keeps in Record.DailySpends.
+func (m *Manager) Query(key string, from, to time.Time) ([]DailySpend, error) {
+	return m.store.Query(key, from, to)
+}
+
+// computeSpend returns positive spend when balance decreases.
+func computeSpend(previous, current float64) float64 {
+	diff := previous - current
+	if diff < 0 {
+		return 0
 	}
-	defer file.Close()
+	return diff
+}
 
-	var history Record
-	if err := json.NewDecoder(file).Decode(&history); err != nil {
-		return Record{}, fmt.Errorf("decode history %q: %v", path, err)
+// BillingCycleKey returns a key that stays stable for as long as now falls
+// within the same billing cycle, changing when a new one starts so Update
+// knows to reset MonthStartBalance/MonthToDateSpend. resetDay <= 0 keeps the
+// plain calendar-month key ("2006-01") Update has always used; resetDay in
+// 1-31 instead keys on the cycle's start date, since a cycle can straddle
+// two calendar months.
+func BillingCycleKey(now time.Time, resetDay int) string {
+	if resetDay <= 0 {
+		return now.Format("2006-01")
 	}
-	return history, nil
+	return BillingCycleStart(now, resetDay).Format("2006-01-02")
 }
 
-func (m *Manager) save(path string, record Record) error {
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return fmt.Errorf("create history dir: %v", err)
+// BillingCycleStart returns the most recent date on or before now whose
+// day-of-month is resetDay, clamped to the last day of a shorter month (e.g.
+// resetDay 31 starts on Feb 28/29 instead of overflowing into March).
+// resetDay <= 0 means "use the calendar month", so it returns the 1st.
+func BillingCycleStart(now time.Time, resetDay int) time.Time {
+	if resetDay <= 0 {
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
 	}
 
-	tmp := path + ".tmp"
-	file, err := os.Create(tmp)
-	if err != nil {
-		return fmt.Errorf("create history tmp %q: %v", tmp, err)
+	year, month := now.Year(), now.Month()
+	day := clampDay(year, month, resetDay)
+	if now.Day() < day {
+		month--
+		if month < time.January {
+			month = time.December
+			year--
+		}
+		day = clampDay(year, month, resetDay)
 	}
+	return time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+}
+
+// BillingCycleEnd returns the start of the billing cycle following the one
+// containing now, i.e. the exclusive end of now's own cycle.
+func BillingCycleEnd(now time.Time, resetDay int) time.Time {
+	start := BillingCycleStart(now, resetDay)
 
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(&record); err != nil {
-		file.Close()
-		return fmt.Errorf("encode history %q: %v", path, err)
+	year, month := start.Year(), start.Month()+1
+	if month > time.December {
+		month = time.January
+		year++
 	}
 
-	if err := file.Close(); err != nil {
-		return fmt.Errorf("close history %q: %v", path, err)
+	day := 1
+	if resetDay > 0 {
+		day = clampDay(year, month, resetDay)
 	}
+	return time.Date(year, month, day, 0, 0, 0, 0, start.Location())
+}
 
-	if err := os.Rename(tmp, path); err != nil {
-		return fmt.Errorf("replace history %q: %v", path, err)
+// clampDay caps day to the number of days in year/month, so a reset day of
+// e.g. 31 degrades gracefully in shorter months.
+func clampDay(year int, month time.Month, day int) int {
+	firstOfNextMonth := time.Date(year, month+1, 1, 0, 0, 0, 0, time.UTC)
+	if lastDay := firstOfNextMonth.AddDate(0, 0, -1).Day(); day > lastDay {
+		return lastDay
 	}
-	return nil
+	return day
 }
 
-// computeSpend returns positive spend when balance decreases.
-func computeSpend(previous, current float64) float64 {
-	diff := previous - current
-	if diff < 0 {
+func plainMean(values []float64) float64 {
+	if len(values) == 0 {
 		return 0
 	}
-	return diff
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+	return total / float64(len(values))
 }
 
-func average(items []DailySpend) float64 {
-	if len(items) == 0 {
+func plainMAD(values []float64, mean float64) float64 {
+	if len(values) == 0 {
 		return 0
 	}
 	var total float64
-	for _, item := range items {
-		total += item.Amount
+	for _, v := range values {
+		total += math.Abs(v - mean)
 	}
-	return total / float64(len(items))
+	return total / float64(len(values))
 }
@@ -0,0 +1,213 @@
+package history
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func Test_BillingCycleStart(t *testing.T) {
+	type args struct {
+		now      time.Time
+		resetDay int
+	}
+	tests := []struct {
+		name string
+		args args
+		want time.Time
+	}{
+		{
+			name: "resetDay 0 starts on the 1st of the calendar month",
+			args: args{now: date(2026, time.March, 15), resetDay: 0},
+			want: date(2026, time.March, 1),
+		},
+		{
+			name: "now on or after resetDay starts this month",
+			args: args{now: date(2026, time.March, 20), resetDay: 15},
+			want: date(2026, time.March, 15),
+		},
+		{
+			name: "now before resetDay starts last month",
+			args: args{now: date(2026, time.March, 10), resetDay: 15},
+			want: date(2026, time.February, 15),
+		},
+		{
+			name: "resetDay past a short month's end clamps to the last day",
+			args: args{now: date(2026, time.February, 20), resetDay: 31},
+			want: date(2026, time.February, 28),
+		},
+		{
+			name: "before-resetDay rollover from January to December",
+			args: args{now: date(2026, time.January, 5), resetDay: 20},
+			want: date(2025, time.December, 20),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BillingCycleStart(tt.args.now, tt.args.resetDay); !got.Equal(tt.want) {
+				t.Errorf("BillingCycleStart() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_BillingCycleEnd(t *testing.T) {
+	type args struct {
+		now      time.Time
+		resetDay int
+	}
+	tests := []struct {
+		name string
+		args args
+		want time.Time
+	}{
+		{
+			name: "resetDay 0 ends on the 1st of next calendar month",
+			args: args{now: date(2026, time.March, 15), resetDay: 0},
+			want: date(2026, time.April, 1),
+		},
+		{
+			name: "resetDay ends on the same day next month",
+			args: args{now: date(2026, time.March, 20), resetDay: 15},
+			want: date(2026, time.April, 15),
+		},
+		{
+			name: "cycle starting on a short-month clamp ends unclamped next month",
+			args: args{now: date(2026, time.February, 20), resetDay: 31},
+			want: date(2026, time.March, 31),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BillingCycleEnd(tt.args.now, tt.args.resetDay); !got.Equal(tt.want) {
+				t.Errorf("BillingCycleEnd() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func Test_updateAnomalyStats(t *testing.T) {
+	type args struct {
+		history     *Record
+		delta       float64
+		billingMode string
+	}
+	type want struct {
+		zScore       float64
+		expectedLow  float64
+		expectedHigh float64
+		warn         bool
+		ewmaCount    int
+		warmupNil    bool
+	}
+	tests := []struct {
+		name string
+		m    *Manager
+		args args
+		want want
+	}{
+		{
+			name: "first warmup sample seeds the mean with zero deviation",
+			m:    NewManager(nil, 3, 0.3, 3.0),
+			args: args{history: &Record{}, delta: 10, billingMode: "prepaid"},
+			want: want{zScore: 0, expectedLow: 10, expectedHigh: 10, warn: false, ewmaCount: 1, warmupNil: false},
+		},
+		{
+			name: "warmup completes and clears WarmupDeltas once EWMACount reaches days",
+			m:    NewManager(nil, 2, 0.3, 3.0),
+			args: args{
+				history:     &Record{EWMACount: 1, EWMAMean: 10, EWMADev: 0, WarmupDeltas: []float64{10}},
+				delta:       20,
+				billingMode: "prepaid",
+			},
+			want: want{
+				zScore:       (20.0 - 15.0) / (robustScale*5 + epsilon),
+				expectedLow:  10,
+				expectedHigh: 20,
+				warn:         false,
+				ewmaCount:    2,
+				warmupNil:    true,
+			},
+		},
+		{
+			name: "steady-state EWMA update blends delta into the existing mean/deviation",
+			m:    NewManager(nil, 2, 0.5, 3.0),
+			args: args{
+				history:     &Record{EWMACount: 5, EWMAMean: 10, EWMADev: 2},
+				delta:       10,
+				billingMode: "prepaid",
+			},
+			want: want{
+				zScore:       0,
+				expectedLow:  9,
+				expectedHigh: 11,
+				warn:         false,
+				ewmaCount:    5,
+			},
+		},
+		{
+			name: "prepaid sign flip from spending to refund warns regardless of z-score",
+			m:    NewManager(nil, 2, 0.5, 3.0),
+			args: args{
+				history:     &Record{EWMACount: 5, EWMAMean: 10, EWMADev: 5},
+				delta:       -5,
+				billingMode: "prepaid",
+			},
+			want: want{
+				zScore:       (-5.0 - 2.5) / (robustScale*6.25 + epsilon),
+				expectedLow:  2.5 - 6.25,
+				expectedHigh: 2.5 + 6.25,
+				warn:         true,
+				ewmaCount:    5,
+			},
+		},
+		{
+			name: "postpaid mode never treats a negative delta as a sign-flip anomaly",
+			m:    NewManager(nil, 2, 0.5, 3.0),
+			args: args{
+				history:     &Record{EWMACount: 5, EWMAMean: 10, EWMADev: 5},
+				delta:       -5,
+				billingMode: "postpaid",
+			},
+			want: want{
+				zScore:       (-5.0 - 2.5) / (robustScale*6.25 + epsilon),
+				expectedLow:  2.5 - 6.25,
+				expectedHigh: 2.5 + 6.25,
+				warn:         false,
+				ewmaCount:    5,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotZ, gotLow, gotHigh, gotWarn := tt.m.updateAnomalyStats(tt.args.history, tt.args.delta, tt.args.billingMode)
+
+			if !almostEqual(gotZ, tt.want.zScore) {
+				t.Errorf("updateAnomalyStats() zScore = %v, want %v", gotZ, tt.want.zScore)
+			}
+			if !almostEqual(gotLow, tt.want.expectedLow) {
+				t.Errorf("updateAnomalyStats() expectedLow = %v, want %v", gotLow, tt.want.expectedLow)
+			}
+			if !almostEqual(gotHigh, tt.want.expectedHigh) {
+				t.Errorf("updateAnomalyStats() expectedHigh = %v, want %v", gotHigh, tt.want.expectedHigh)
+			}
+			if gotWarn != tt.want.warn {
+				t.Errorf("updateAnomalyStats() warn = %v, want %v", gotWarn, tt.want.warn)
+			}
+			if tt.args.history.EWMACount != tt.want.ewmaCount {
+				t.Errorf("updateAnomalyStats() EWMACount = %v, want %v", tt.args.history.EWMACount, tt.want.ewmaCount)
+			}
+			if tt.want.warmupNil && tt.args.history.WarmupDeltas != nil {
+				t.Errorf("updateAnomalyStats() WarmupDeltas = %v, want nil after warmup completes", tt.args.history.WarmupDeltas)
+			}
+		})
+	}
+}
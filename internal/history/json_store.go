@@ -0,0 +1,87 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// JSONFileStore is the original Store implementation: one JSON file per key,
+// treating key as the file's path. Writes are atomic (temp file + rename).
+type JSONFileStore struct{}
+
+// NewJSONFileStore builds the default file-based Store.
+func NewJSONFileStore() *JSONFileStore {
+	return &JSONFileStore{}
+}
+
+func (s *JSONFileStore) Load(key string) (Record, error) {
+	file, err := os.Open(key)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Record{}, nil
+		}
+		return Record{}, fmt.Errorf("open history %q: %v", key, err)
+	}
+	defer file.Close()
+
+	var record Record
+	if err := json.NewDecoder(file).Decode(&record); err != nil {
+		return Record{}, fmt.Errorf("decode history %q: %v", key, err)
+	}
+	return record, nil
+}
+
+func (s *JSONFileStore) Save(key string, record Record) error {
+	if err := os.MkdirAll(filepath.Dir(key), 0o755); err != nil {
+		return fmt.Errorf("create history dir: %v", err)
+	}
+
+	tmp := key + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("create history tmp %q: %v", tmp, err)
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(&record); err != nil {
+		file.Close()
+		return fmt.Errorf("encode history %q: %v", key, err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("close history %q: %v", key, err)
+	}
+
+	if err := os.Rename(tmp, key); err != nil {
+		return fmt.Errorf("replace history %q: %v", key, err)
+	}
+	return nil
+}
+
+// Query filters key's persisted daily spends to [from, to]. The JSON store
+// only ever retains Manager's fixed averaging window, so a window wider than
+// that simply returns what's on disk; use the SQL store for true unbounded
+// range queries.
+func (s *JSONFileStore) Query(key string, from, to time.Time) ([]DailySpend, error) {
+	record, err := s.Load(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var spends []DailySpend
+	for _, spend := range record.DailySpends {
+		day, err := time.Parse("2006-01-02", spend.Date)
+		if err != nil {
+			continue
+		}
+		if day.Before(from) || day.After(to) {
+			continue
+		}
+		spends = append(spends, spend)
+	}
+	return spends, nil
+}
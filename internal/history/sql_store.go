@@ -0,0 +1,266 @@
+package history
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+)
+
+// SQLStore keeps one row per key in history_records (the running EWMA/MTD
+// state Manager needs) and one row per (key, date) in daily_spends, so Query
+// can span arbitrary windows instead of the fixed days window baked into
+// Manager's in-memory averaging. Reads and writes use database/sql directly;
+// sqlite and postgres are supported by selecting the matching driver name.
+type SQLStore struct {
+	db     *sql.DB
+	driver string
+}
+
+// OpenSQLStore opens dsn with the named driver ("sqlite" or "postgres"),
+// applies its migrations, and returns a ready-to-use SQLStore.
+func OpenSQLStore(driver, dsn string, maxOpenConns int) (*SQLStore, error) {
+	driverName := normalizeDriver(driver)
+
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open %s database: %v", driverName, err)
+	}
+	if maxOpenConns > 0 {
+		db.SetMaxOpenConns(maxOpenConns)
+	}
+
+	store := &SQLStore{db: db, driver: driverName}
+	if err := store.migrate(); err != nil {
+		return nil, fmt.Errorf("migrate history store: %v", err)
+	}
+	return store, nil
+}
+
+func normalizeDriver(driver string) string {
+	if strings.ToLower(strings.TrimSpace(driver)) == "postgres" {
+		return "postgres"
+	}
+	return "sqlite"
+}
+
+func (s *SQLStore) migrate() error {
+	if _, err := s.db.Exec(`
+CREATE TABLE IF NOT EXISTS history_records (
+	service             TEXT PRIMARY KEY,
+	last_balance        REAL NOT NULL,
+	last_native_balance REAL NOT NULL DEFAULT 0,
+	last_updated        TEXT NOT NULL DEFAULT '',
+	month_key           TEXT NOT NULL DEFAULT '',
+	month_start_balance REAL NOT NULL DEFAULT 0,
+	month_to_date_spend REAL NOT NULL DEFAULT 0,
+	ewma_mean           REAL NOT NULL DEFAULT 0,
+	ewma_dev            REAL NOT NULL DEFAULT 0,
+	ewma_count          INTEGER NOT NULL DEFAULT 0,
+	warmup_deltas       TEXT NOT NULL DEFAULT '[]'
+)`); err != nil {
+		return err
+	}
+
+	if _, err := s.db.Exec(`
+CREATE TABLE IF NOT EXISTS daily_spends (
+	service         TEXT NOT NULL,
+	date            TEXT NOT NULL,
+	amount          REAL NOT NULL,
+	native_amount   REAL NOT NULL DEFAULT 0,
+	native_currency TEXT NOT NULL DEFAULT '',
+	PRIMARY KEY (service, date)
+)`); err != nil {
+		return err
+	}
+
+	// The three ADD COLUMN calls below backfill databases created before
+	// chunk1-5 added native-currency tracking to Record/DailySpend; a fresh
+	// database already has the columns from the CREATE TABLE above.
+	for _, col := range []struct{ table, column, ddl string }{
+		{"history_records", "last_native_balance", "REAL NOT NULL DEFAULT 0"},
+		{"daily_spends", "native_amount", "REAL NOT NULL DEFAULT 0"},
+		{"daily_spends", "native_currency", "TEXT NOT NULL DEFAULT ''"},
+	} {
+		if err := s.addColumnIfMissing(col.table, col.column, col.ddl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addColumnIfMissing adds column to table, tolerating the error both sqlite
+// and postgres return when it already exists. Neither driver's "ADD COLUMN"
+// supports "IF NOT EXISTS" portably across the versions this store targets,
+// so the duplicate-column error from an already-migrated database is the
+// only signal available that the column is already there.
+func (s *SQLStore) addColumnIfMissing(table, column, ddl string) error {
+	_, err := s.db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, ddl))
+	if err == nil {
+		return nil
+	}
+	msg := strings.ToLower(err.Error())
+	if strings.Contains(msg, "duplicate column") || strings.Contains(msg, "already exists") {
+		return nil
+	}
+	return fmt.Errorf("add column %s.%s: %v", table, column, err)
+}
+
+// placeholder returns the driver-appropriate bind parameter for position n
+// (1-based): sqlite takes "?", postgres takes "$n".
+func (s *SQLStore) placeholder(n int) string {
+	if s.driver == "postgres" {
+		return fmt.Sprintf("$%d", n)
+	}
+	return "?"
+}
+
+func (s *SQLStore) Load(key string) (Record, error) {
+	var record Record
+	var warmupJSON string
+
+	row := s.db.QueryRow(fmt.Sprintf(`
+SELECT last_balance, last_native_balance, last_updated, month_key, month_start_balance, month_to_date_spend,
+       ewma_mean, ewma_dev, ewma_count, warmup_deltas
+FROM history_records WHERE service = %s`, s.placeholder(1)), key)
+
+	switch err := row.Scan(&record.LastBalance, &record.LastNativeBalance, &record.LastUpdated, &record.MonthKey, &record.MonthStartBalance,
+		&record.MonthToDateSpend, &record.EWMAMean, &record.EWMADev, &record.EWMACount, &warmupJSON); {
+	case err == sql.ErrNoRows:
+		return Record{}, nil
+	case err != nil:
+		return Record{}, fmt.Errorf("load history %q: %v", key, err)
+	}
+
+	if warmupJSON != "" {
+		if err := json.Unmarshal([]byte(warmupJSON), &record.WarmupDeltas); err != nil {
+			return Record{}, fmt.Errorf("decode warmup deltas %q: %v", key, err)
+		}
+	}
+
+	rows, err := s.db.Query(fmt.Sprintf(`
+SELECT date, amount, native_amount, native_currency FROM daily_spends WHERE service = %s ORDER BY date DESC LIMIT 400`, s.placeholder(1)), key)
+	if err != nil {
+		return Record{}, fmt.Errorf("load daily spends %q: %v", key, err)
+	}
+	defer rows.Close()
+
+	var spends []DailySpend
+	for rows.Next() {
+		var spend DailySpend
+		if err := rows.Scan(&spend.Date, &spend.Amount, &spend.NativeAmount, &spend.NativeCurrency); err != nil {
+			return Record{}, fmt.Errorf("scan daily spend %q: %v", key, err)
+		}
+		spends = append(spends, spend)
+	}
+	if err := rows.Err(); err != nil {
+		return Record{}, fmt.Errorf("iterate daily spends %q: %v", key, err)
+	}
+
+	for i, j := 0, len(spends)-1; i < j; i, j = i+1, j-1 {
+		spends[i], spends[j] = spends[j], spends[i]
+	}
+	record.DailySpends = spends
+
+	return record, nil
+}
+
+// Save upserts the running state and the record's daily spend rows inside a
+// single transaction, so concurrent writers for different keys never leave a
+// torn write between history_records and daily_spends.
+func (s *SQLStore) Save(key string, record Record) error {
+	warmupJSON, err := json.Marshal(record.WarmupDeltas)
+	if err != nil {
+		return fmt.Errorf("encode warmup deltas: %v", err)
+	}
+
+	tx, err := s.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("begin history tx: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(s.upsertRecordSQL(), key, record.LastBalance, record.LastNativeBalance, record.LastUpdated, record.MonthKey,
+		record.MonthStartBalance, record.MonthToDateSpend, record.EWMAMean, record.EWMADev, record.EWMACount, string(warmupJSON)); err != nil {
+		return fmt.Errorf("upsert history record %q: %v", key, err)
+	}
+
+	for _, spend := range record.DailySpends {
+		if _, err := tx.Exec(s.upsertSpendSQL(), key, spend.Date, spend.Amount, spend.NativeAmount, spend.NativeCurrency); err != nil {
+			return fmt.Errorf("upsert daily spend %q/%s: %v", key, spend.Date, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit history tx %q: %v", key, err)
+	}
+	return nil
+}
+
+func (s *SQLStore) upsertRecordSQL() string {
+	conflictTarget := "excluded"
+	if s.driver == "postgres" {
+		conflictTarget = "EXCLUDED"
+	}
+	return fmt.Sprintf(`
+INSERT INTO history_records (service, last_balance, last_native_balance, last_updated, month_key, month_start_balance,
+	month_to_date_spend, ewma_mean, ewma_dev, ewma_count, warmup_deltas)
+VALUES (%s,%s,%s,%s,%s,%s,%s,%s,%s,%s,%s)
+ON CONFLICT (service) DO UPDATE SET
+	last_balance = %[12]s.last_balance,
+	last_native_balance = %[12]s.last_native_balance,
+	last_updated = %[12]s.last_updated,
+	month_key = %[12]s.month_key,
+	month_start_balance = %[12]s.month_start_balance,
+	month_to_date_spend = %[12]s.month_to_date_spend,
+	ewma_mean = %[12]s.ewma_mean,
+	ewma_dev = %[12]s.ewma_dev,
+	ewma_count = %[12]s.ewma_count,
+	warmup_deltas = %[12]s.warmup_deltas`,
+		s.placeholder(1), s.placeholder(2), s.placeholder(3), s.placeholder(4), s.placeholder(5),
+		s.placeholder(6), s.placeholder(7), s.placeholder(8), s.placeholder(9), s.placeholder(10), s.placeholder(11),
+		conflictTarget)
+}
+
+func (s *SQLStore) upsertSpendSQL() string {
+	conflictTarget := "excluded"
+	if s.driver == "postgres" {
+		conflictTarget = "EXCLUDED"
+	}
+	return fmt.Sprintf(`
+INSERT INTO daily_spends (service, date, amount, native_amount, native_currency) VALUES (%s,%s,%s,%s,%s)
+ON CONFLICT (service, date) DO UPDATE SET
+	amount = %[6]s.amount,
+	native_amount = %[6]s.native_amount,
+	native_currency = %[6]s.native_currency`,
+		s.placeholder(1), s.placeholder(2), s.placeholder(3), s.placeholder(4), s.placeholder(5), conflictTarget)
+}
+
+// Query returns key's daily spends between from and to (inclusive),
+// unconstrained by Manager's fixed averaging window.
+func (s *SQLStore) Query(key string, from, to time.Time) ([]DailySpend, error) {
+	rows, err := s.db.Query(fmt.Sprintf(`
+SELECT date, amount, native_amount, native_currency FROM daily_spends
+WHERE service = %s AND date >= %s AND date <= %s
+ORDER BY date ASC`, s.placeholder(1), s.placeholder(2), s.placeholder(3)),
+		key, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("query daily spends %q: %v", key, err)
+	}
+	defer rows.Close()
+
+	var spends []DailySpend
+	for rows.Next() {
+		var spend DailySpend
+		if err := rows.Scan(&spend.Date, &spend.Amount, &spend.NativeAmount, &spend.NativeCurrency); err != nil {
+			return nil, fmt.Errorf("scan daily spend %q: %v", key, err)
+		}
+		spends = append(spends, spend)
+	}
+	return spends, rows.Err()
+}
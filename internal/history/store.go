@@ -0,0 +1,14 @@
+package history
+
+import "time"
+
+// Store persists Records and their daily spend rows, keyed by an opaque
+// identifier (ordinarily a service's configured history file path or name).
+// Manager drives Load/Save on every Update; Query answers arbitrary
+// date-range reads without being limited by the fixed averaging window
+// Manager keeps in Record.DailySpends.
+type Store interface {
+	Load(key string) (Record, error)
+	Save(key string, record Record) error
+	Query(key string, from, to time.Time) ([]DailySpend, error)
+}
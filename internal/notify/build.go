@@ -0,0 +1,40 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sarff/FundsPulse/internal/config"
+)
+
+// Build constructs a Multiplexer from the configured notifier list.
+func Build(cfgs []config.NotifierConfig) (*Multiplexer, error) {
+	transports := make([]Notifier, 0, len(cfgs))
+
+	for _, cfg := range cfgs {
+		transport, err := buildOne(cfg)
+		if err != nil {
+			return nil, err
+		}
+		transports = append(transports, transport)
+	}
+
+	return NewMultiplexer(transports...), nil
+}
+
+func buildOne(cfg config.NotifierConfig) (Notifier, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.Type)) {
+	case "telegram":
+		return NewTelegram(cfg.Telegram.Token, cfg.Telegram.ChatIDs)
+	case "discord":
+		return NewDiscord(cfg.Discord.WebhookURL)
+	case "slack":
+		return NewSlack(cfg.Slack.WebhookURL)
+	case "email":
+		return NewEmail(cfg.Email.Host, cfg.Email.Port, cfg.Email.Username, cfg.Email.Password, cfg.Email.From, cfg.Email.To)
+	case "webhook":
+		return NewWebhook(cfg.Webhook.URL, cfg.Webhook.Secret, cfg.Webhook.SignatureHdr)
+	default:
+		return nil, fmt.Errorf("notifier: unknown type %q", cfg.Type)
+	}
+}
@@ -0,0 +1,64 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Discord delivers notifications to a Discord incoming webhook.
+type Discord struct {
+	webhookURL string
+	http       *http.Client
+}
+
+// NewDiscord builds a Discord webhook transport.
+func NewDiscord(webhookURL string) (*Discord, error) {
+	if webhookURL == "" {
+		return nil, errors.New("discord webhook url is required")
+	}
+	return &Discord{webhookURL: webhookURL, http: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+// NotifyBalance posts a balance report message.
+func (d *Discord) NotifyBalance(ctx context.Context, message string) error {
+	return d.post(ctx, message)
+}
+
+// NotifyStatic posts a static payment reminder message.
+func (d *Discord) NotifyStatic(ctx context.Context, message string) error {
+	return d.post(ctx, message)
+}
+
+// NotifyError posts a service check failure.
+func (d *Discord) NotifyError(ctx context.Context, service string, svcErr error) error {
+	return d.post(ctx, fmt.Sprintf("Service: %s\nError: %v", service, svcErr))
+}
+
+func (d *Discord) post(ctx context.Context, content string) error {
+	body, err := json.Marshal(map[string]string{"content": content})
+	if err != nil {
+		return fmt.Errorf("encode discord payload: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build discord request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("send discord message: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("send discord message: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/smtp"
+	"strings"
+)
+
+// Email delivers notifications over SMTP.
+type Email struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+	to       []string
+}
+
+// NewEmail builds an SMTP email transport.
+func NewEmail(host string, port int, username, password, from string, to []string) (*Email, error) {
+	if host == "" {
+		return nil, errors.New("email host is required")
+	}
+	if len(to) == 0 {
+		return nil, errors.New("email: at least one recipient is required")
+	}
+	return &Email{host: host, port: port, username: username, password: password, from: from, to: to}, nil
+}
+
+// NotifyBalance sends a balance report email.
+func (e *Email) NotifyBalance(ctx context.Context, message string) error {
+	return e.send(ctx, "FundsPulse balance report", message)
+}
+
+// NotifyStatic sends a static payment reminder email.
+func (e *Email) NotifyStatic(ctx context.Context, message string) error {
+	return e.send(ctx, "FundsPulse payment reminder", message)
+}
+
+// NotifyError sends a service check failure email.
+func (e *Email) NotifyError(ctx context.Context, service string, svcErr error) error {
+	return e.send(ctx, fmt.Sprintf("FundsPulse error: %s", service), fmt.Sprintf("Service: %s\nError: %v", service, svcErr))
+}
+
+func (e *Email) send(ctx context.Context, subject, body string) error {
+	addr := fmt.Sprintf("%s:%d", e.host, e.port)
+
+	var auth smtp.Auth
+	if e.username != "" {
+		auth = smtp.PlainAuth("", e.username, e.password, e.host)
+	}
+
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s", e.from, strings.Join(e.to, ", "), subject, body)
+
+	if err := smtp.SendMail(addr, auth, e.from, e.to, []byte(msg)); err != nil {
+		return fmt.Errorf("send email: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package notify
+
+import (
+	"context"
+	"errors"
+)
+
+// Multiplexer fans out every notification to a configured list of transports
+// so Checker.RunOnce can treat notification targets uniformly.
+type Multiplexer struct {
+	transports []Notifier
+}
+
+// NewMultiplexer builds a multiplexer over the given transports.
+func NewMultiplexer(transports ...Notifier) *Multiplexer {
+	return &Multiplexer{transports: transports}
+}
+
+// NotifyBalance fans out a balance report to every transport.
+func (m *Multiplexer) NotifyBalance(ctx context.Context, message string) error {
+	return m.fanOut(func(n Notifier) error { return n.NotifyBalance(ctx, message) })
+}
+
+// NotifyStatic fans out a static payment reminder to every transport.
+func (m *Multiplexer) NotifyStatic(ctx context.Context, message string) error {
+	return m.fanOut(func(n Notifier) error { return n.NotifyStatic(ctx, message) })
+}
+
+// NotifyError fans out a service error to every transport.
+func (m *Multiplexer) NotifyError(ctx context.Context, service string, svcErr error) error {
+	return m.fanOut(func(n Notifier) error { return n.NotifyError(ctx, service, svcErr) })
+}
+
+func (m *Multiplexer) fanOut(call func(Notifier) error) error {
+	var errs []error
+	for _, transport := range m.transports {
+		if err := call(transport); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,12 @@
+package notify
+
+import "context"
+
+// Notifier delivers balance reports, static-payment reminders, and service
+// errors to a single configured transport (Telegram, Discord, Slack, email,
+// or a generic webhook).
+type Notifier interface {
+	NotifyBalance(ctx context.Context, message string) error
+	NotifyStatic(ctx context.Context, message string) error
+	NotifyError(ctx context.Context, service string, svcErr error) error
+}
@@ -0,0 +1,64 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Slack delivers notifications to a Slack incoming webhook.
+type Slack struct {
+	webhookURL string
+	http       *http.Client
+}
+
+// NewSlack builds a Slack webhook transport.
+func NewSlack(webhookURL string) (*Slack, error) {
+	if webhookURL == "" {
+		return nil, errors.New("slack webhook url is required")
+	}
+	return &Slack{webhookURL: webhookURL, http: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+// NotifyBalance posts a balance report message.
+func (s *Slack) NotifyBalance(ctx context.Context, message string) error {
+	return s.post(ctx, message)
+}
+
+// NotifyStatic posts a static payment reminder message.
+func (s *Slack) NotifyStatic(ctx context.Context, message string) error {
+	return s.post(ctx, message)
+}
+
+// NotifyError posts a service check failure.
+func (s *Slack) NotifyError(ctx context.Context, service string, svcErr error) error {
+	return s.post(ctx, fmt.Sprintf("Service: %s\nError: %v", service, svcErr))
+}
+
+func (s *Slack) post(ctx context.Context, text string) error {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return fmt.Errorf("encode slack payload: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build slack request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("send slack message: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("send slack message: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -7,23 +7,38 @@ import (
 	"github.com/mymmrac/telego"
 )
 
-// Telegram delivers balance updates to configured chats.
+// Telegram delivers notifications to configured chats via the Telegram bot API.
 type Telegram struct {
-	bot *telego.Bot
+	bot     *telego.Bot
+	chatIDs []int64
 }
 
-// NewTelegram builds telego bot instance.
-func NewTelegram(token string) (*Telegram, error) {
+// NewTelegram builds telego bot instance bound to chatIDs.
+func NewTelegram(token string, chatIDs []int64) (*Telegram, error) {
 	bot, err := telego.NewBot(token)
 	if err != nil {
 		return nil, fmt.Errorf("create telegram bot: %v", err)
 	}
-	return &Telegram{bot: bot}, nil
+	return &Telegram{bot: bot, chatIDs: chatIDs}, nil
 }
 
-// Notify sends message to every chat id.
-func (t *Telegram) Notify(ctx context.Context, chatIDs []int64, message string) error {
-	for _, chatID := range chatIDs {
+// NotifyBalance sends a balance report message.
+func (t *Telegram) NotifyBalance(ctx context.Context, message string) error {
+	return t.send(ctx, message)
+}
+
+// NotifyStatic sends a static payment reminder message.
+func (t *Telegram) NotifyStatic(ctx context.Context, message string) error {
+	return t.send(ctx, message)
+}
+
+// NotifyError reports a service check failure.
+func (t *Telegram) NotifyError(ctx context.Context, service string, svcErr error) error {
+	return t.send(ctx, fmt.Sprintf("Service: %s\nError: %v", service, svcErr))
+}
+
+func (t *Telegram) send(ctx context.Context, message string) error {
+	for _, chatID := range t.chatIDs {
 		params := &telego.SendMessageParams{
 			ChatID: telego.ChatID{ID: chatID},
 			Text:   message,
@@ -0,0 +1,91 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Webhook delivers notifications as a generic JSON POST, optionally signed
+// with HMAC-SHA256 so the receiver can authenticate the sender.
+type Webhook struct {
+	url          string
+	secret       string
+	signatureHdr string
+	http         *http.Client
+}
+
+type webhookPayload struct {
+	Kind    string `json:"kind"`
+	Service string `json:"service,omitempty"`
+	Message string `json:"message"`
+}
+
+// NewWebhook builds a generic webhook transport. When secret is non-empty,
+// every request carries an HMAC-SHA256 signature in signatureHdr (default
+// "X-FundsPulse-Signature").
+func NewWebhook(url, secret, signatureHdr string) (*Webhook, error) {
+	if url == "" {
+		return nil, errors.New("webhook url is required")
+	}
+	if signatureHdr == "" {
+		signatureHdr = "X-FundsPulse-Signature"
+	}
+	return &Webhook{url: url, secret: secret, signatureHdr: signatureHdr, http: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+// NotifyBalance posts a balance report payload.
+func (w *Webhook) NotifyBalance(ctx context.Context, message string) error {
+	return w.post(ctx, webhookPayload{Kind: "balance", Message: message})
+}
+
+// NotifyStatic posts a static payment reminder payload.
+func (w *Webhook) NotifyStatic(ctx context.Context, message string) error {
+	return w.post(ctx, webhookPayload{Kind: "static", Message: message})
+}
+
+// NotifyError posts a service error payload.
+func (w *Webhook) NotifyError(ctx context.Context, service string, svcErr error) error {
+	return w.post(ctx, webhookPayload{Kind: "error", Service: service, Message: svcErr.Error()})
+}
+
+func (w *Webhook) post(ctx context.Context, payload webhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode webhook payload: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build webhook request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if w.secret != "" {
+		req.Header.Set(w.signatureHdr, signBody(w.secret, body))
+	}
+
+	resp, err := w.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("send webhook: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("send webhook: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func signBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -2,8 +2,18 @@ package service
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"hash"
+	"net/url"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,165 +23,247 @@ import (
 	"github.com/sarff/FundsPulse/internal/config"
 )
 
-// Client calls remote balance APIs.
+// Client wraps the shared HTTP mechanics (timeouts, retries, header/query/body
+// expansion, per-host rate limiting) that every Fetcher implementation builds
+// requests on top of.
 type Client struct {
-	http *resty.Client
+	http     *resty.Client
+	limiters *hostLimiter
 }
 
-// BalanceEntry carries a single balance and optional currency decoded from API response.
+// BalanceEntry carries a single balance and optional currency decoded from
+// API response. NativeAmount/NativeCurrency hold the as-fetched values;
+// Amount/Currency are overwritten with the fx-converted reporting-currency
+// values when Registry has a Rates provider configured.
 type BalanceEntry struct {
-	Amount   float64
-	Currency string
+	Amount         float64
+	Currency       string
+	NativeAmount   float64
+	NativeCurrency string
 }
 
-// NewClient builds resty-based client with sane defaults.
-func NewClient() *Client {
+// NewClient builds resty-based client with sane defaults. perHostRPS caps
+// outgoing requests per destination host (0 disables limiting); perHostBurst
+// is the token bucket's burst size.
+func NewClient(perHostRPS float64, perHostBurst int) *Client {
 	http := resty.New()
 	http.SetTimeout(30 * time.Second)
 	http.SetRetryCount(2)
 	http.SetRetryWaitTime(2 * time.Second)
 	http.SetRetryMaxWaitTime(10 * time.Second)
 
-	return &Client{http: http}
+	return &Client{http: http, limiters: newHostLimiter(perHostRPS, perHostBurst)}
 }
 
-// FetchBalance requests service balance entries and optional currency values.
-func (c *Client) FetchBalance(ctx context.Context, cfg config.ServiceConfig) ([]BalanceEntry, error) {
-	var authToken string
-	if cfg.Auth != nil {
-		authReq, authCancel := c.prepareRequest(ctx, cfg.Auth.Request)
-		if authCancel != nil {
-			defer authCancel()
-		}
+// prepareRequest builds a resty request from cfg and blocks until cfg.URL's
+// host clears the per-host rate limiter (or ctx ends first).
+func (c *Client) prepareRequest(ctx context.Context, cfg config.RequestConfig) (*resty.Request, context.CancelFunc, error) {
+	callCtx := ctx
+	var cancel context.CancelFunc
+	if cfg.TimeoutSeconds > 0 {
+		callCtx, cancel = context.WithTimeout(ctx, time.Duration(cfg.TimeoutSeconds)*time.Second)
+	}
 
-		authMethod := strings.ToUpper(strings.TrimSpace(cfg.Auth.Request.Method))
-		if authMethod == "" {
-			authMethod = "POST"
+	if err := c.limiters.wait(callCtx, os.ExpandEnv(cfg.URL)); err != nil {
+		if cancel != nil {
+			cancel()
 		}
+		return nil, nil, fmt.Errorf("rate limit wait: %v", err)
+	}
 
-		authResp, authErr := authReq.Execute(authMethod, os.ExpandEnv(cfg.Auth.Request.URL))
-		if authErr != nil {
-			return nil, fmt.Errorf("auth %s: %v", cfg.Name, authErr)
-		}
+	req := c.http.R()
+	req.SetContext(callCtx)
 
-		if !authResp.IsSuccess() {
-			return nil, fmt.Errorf("auth %s: unexpected status %d", cfg.Name, authResp.StatusCode())
-		}
+	for key, value := range cfg.Headers {
+		req.SetHeader(key, os.ExpandEnv(value))
+	}
 
-		tokenValue := gjson.GetBytes(authResp.Body(), cfg.Auth.TokenPath)
-		if !tokenValue.Exists() {
-			return nil, fmt.Errorf("auth %s: token path %q not found", cfg.Name, cfg.Auth.TokenPath)
-		}
+	for key, value := range cfg.Query {
+		req.SetQueryParam(key, os.ExpandEnv(value))
+	}
 
-		authToken = strings.TrimSpace(tokenValue.String())
-		if authToken == "" {
-			return nil, fmt.Errorf("auth %s: token is empty", cfg.Name)
-		}
+	if cfg.Body != nil {
+		req.SetBody(expandPlaceholders(cfg.Body))
 	}
 
-	req, cancel := c.prepareRequest(ctx, cfg.Request)
-	if cancel != nil {
-		defer cancel()
+	return req, cancel, nil
+}
+
+// resolveAuthToken runs the optional pre-request auth flow and extracts the
+// token named by auth.TokenPath, shared by every Fetcher that supports it.
+func (c *Client) resolveAuthToken(ctx context.Context, name string, auth *config.AuthConfig) (string, error) {
+	if auth == nil || strings.ToLower(strings.TrimSpace(auth.Type)) == "signed" {
+		return "", nil
 	}
 
-	if cfg.Auth != nil {
-		headerName := strings.TrimSpace(cfg.Auth.Header)
-		if headerName != "" {
-			prefix := os.ExpandEnv(cfg.Auth.Prefix)
-			req.SetHeader(headerName, prefix+authToken)
-		}
+	authReq, authCancel, err := c.prepareRequest(ctx, auth.Request)
+	if authCancel != nil {
+		defer authCancel()
+	}
+	if err != nil {
+		return "", fmt.Errorf("auth %s: %v", name, err)
 	}
 
-	method := strings.ToUpper(strings.TrimSpace(cfg.Request.Method))
-	if method == "" {
-		method = "GET"
+	authMethod := strings.ToUpper(strings.TrimSpace(auth.Request.Method))
+	if authMethod == "" {
+		authMethod = "POST"
 	}
 
-	resp, err := req.Execute(method, os.ExpandEnv(cfg.Request.URL))
+	authResp, err := authReq.Execute(authMethod, os.ExpandEnv(auth.Request.URL))
 	if err != nil {
-		return nil, fmt.Errorf("request %s: %v", cfg.Name, err)
+		return "", fmt.Errorf("auth %s: %v", name, err)
 	}
 
-	if !resp.IsSuccess() {
-		return nil, fmt.Errorf("request %s: unexpected status %d", cfg.Name, resp.StatusCode())
+	if !authResp.IsSuccess() {
+		return "", fmt.Errorf("auth %s: unexpected status %d", name, authResp.StatusCode())
 	}
 
-	payload := resp.Body()
-	balanceValue := gjson.GetBytes(payload, cfg.Response.BalancePath)
+	tokenValue := gjson.GetBytes(authResp.Body(), auth.TokenPath)
+	if !tokenValue.Exists() {
+		return "", fmt.Errorf("auth %s: token path %q not found", name, auth.TokenPath)
+	}
 
-	if !balanceValue.Exists() {
-		return nil, fmt.Errorf("request %s: balance path %q not found", cfg.Name, cfg.Response.BalancePath)
+	token := strings.TrimSpace(tokenValue.String())
+	if token == "" {
+		return "", fmt.Errorf("auth %s: token is empty", name)
 	}
+	return token, nil
+}
 
-	scale := cfg.Response.BalanceScale
-	if scale == 0 {
-		scale = 1
+// applyAuthHeader injects the resolved token into the request using auth's
+// configured header and prefix. It is a no-op when auth is nil.
+func applyAuthHeader(req *resty.Request, auth *config.AuthConfig, token string) {
+	if auth == nil {
+		return
+	}
+	headerName := strings.TrimSpace(auth.Header)
+	if headerName == "" {
+		return
 	}
+	req.SetHeader(headerName, os.ExpandEnv(auth.Prefix)+token)
+}
 
-	var entries []BalanceEntry
-	if cfg.Response.Multiple {
-		if !balanceValue.IsArray() {
-			return nil, fmt.Errorf("request %s: balance path %q is not an array", cfg.Name, cfg.Response.BalancePath)
-		}
+// applySignedAuth HMAC-signs req in place per auth.Signed, so exchange-style
+// APIs (MAX, Binance, Kraken, ...) that require a per-request signature
+// rather than a fetched bearer token can be supported without per-service
+// code. It is a no-op when auth is nil or not configured for signed auth.
+func applySignedAuth(req *resty.Request, method, rawURL string, body any, auth *config.AuthConfig) error {
+	if auth == nil || strings.ToLower(strings.TrimSpace(auth.Type)) != "signed" {
+		return nil
+	}
+	signed := auth.Signed
 
-		values := balanceValue.Array()
-		entries = make([]BalanceEntry, 0, len(values))
-		for _, item := range values {
-			entries = append(entries, BalanceEntry{Amount: item.Float() * scale})
-		}
-	} else {
-		entries = []BalanceEntry{{Amount: balanceValue.Float() * scale}}
-	}
-
-	if len(entries) == 0 {
-		return nil, fmt.Errorf("request %s: no balances found", cfg.Name)
-	}
-
-	if cfg.Response.CurrencyField != "" {
-		currencyValue := gjson.GetBytes(payload, cfg.Response.CurrencyField)
-		if currencyValue.Exists() {
-			if cfg.Response.Multiple && currencyValue.IsArray() {
-				currencies := currencyValue.Array()
-				for i := range entries {
-					if i < len(currencies) {
-						entries[i].Currency = strings.TrimSpace(currencies[i].String())
-					}
-				}
-			} else {
-				currency := strings.TrimSpace(currencyValue.String())
-				for i := range entries {
-					entries[i].Currency = currency
-				}
-			}
+	apiKey := os.Getenv(signed.APIKeyEnv)
+	if apiKey == "" {
+		return fmt.Errorf("signed auth: env var %q is not set", signed.APIKeyEnv)
+	}
+	apiSecret := os.Getenv(signed.APISecretEnv)
+	if apiSecret == "" {
+		return fmt.Errorf("signed auth: env var %q is not set", signed.APISecretEnv)
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("signed auth: parse url: %v", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+
+	var bodyJSON []byte
+	switch v := body.(type) {
+	case nil:
+	case string:
+		bodyJSON = []byte(v)
+	default:
+		bodyJSON, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("signed auth: encode body: %v", err)
 		}
 	}
 
-	return entries, nil
-}
+	canonical, err := buildCanonicalString(signed.SignedFields, method, parsed, timestamp, bodyJSON)
+	if err != nil {
+		return fmt.Errorf("signed auth: %v", err)
+	}
 
-func (c *Client) prepareRequest(ctx context.Context, cfg config.RequestConfig) (*resty.Request, context.CancelFunc) {
-	req := c.http.R()
+	signature, err := signHMAC(signed.Algorithm, apiSecret, canonical, signed.Encoding)
+	if err != nil {
+		return fmt.Errorf("signed auth: %v", err)
+	}
 
-	callCtx := ctx
-	var cancel context.CancelFunc
-	if cfg.TimeoutSeconds > 0 {
-		callCtx, cancel = context.WithTimeout(ctx, time.Duration(cfg.TimeoutSeconds)*time.Second)
+	req.SetHeader(signed.SignatureHeader, signature)
+	if signed.AccessKeyHeader != "" {
+		req.SetHeader(signed.AccessKeyHeader, apiKey)
 	}
-	req.SetContext(callCtx)
+	if signed.TimestampHeader != "" {
+		req.SetHeader(signed.TimestampHeader, timestamp)
+	}
+	return nil
+}
 
-	for key, value := range cfg.Headers {
-		req.SetHeader(key, os.ExpandEnv(value))
+// buildCanonicalString concatenates the requested fields, in order, into the
+// string that gets HMAC-signed. Supported field names: method, path, query
+// (sorted key=value pairs joined by '&'), timestamp, body (the JSON-encoded
+// request body, base64'd).
+func buildCanonicalString(fields []string, method string, parsed *url.URL, timestamp string, bodyJSON []byte) (string, error) {
+	var b strings.Builder
+	for _, field := range fields {
+		switch strings.ToLower(strings.TrimSpace(field)) {
+		case "method":
+			b.WriteString(strings.ToUpper(method))
+		case "path":
+			b.WriteString(parsed.Path)
+		case "query":
+			b.WriteString(sortedQuery(parsed.Query()))
+		case "timestamp":
+			b.WriteString(timestamp)
+		case "body":
+			b.WriteString(base64.StdEncoding.EncodeToString(bodyJSON))
+		default:
+			return "", fmt.Errorf("unknown signed field %q", field)
+		}
 	}
+	return b.String(), nil
+}
 
-	for key, value := range cfg.Query {
-		req.SetQueryParam(key, os.ExpandEnv(value))
+// sortedQuery renders query params sorted by key, as most exchange APIs
+// require a deterministic ordering for the signed string.
+func sortedQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
 	}
+	sort.Strings(keys)
 
-	if cfg.Body != nil {
-		req.SetBody(expandPlaceholders(cfg.Body))
+	var b strings.Builder
+	for i, key := range keys {
+		if i > 0 {
+			b.WriteByte('&')
+		}
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(values.Get(key))
+	}
+	return b.String()
+}
+
+func signHMAC(algorithm, secret, canonical, encoding string) (string, error) {
+	var newHash func() hash.Hash
+	switch strings.ToLower(strings.TrimSpace(algorithm)) {
+	case "hmac-sha512":
+		newHash = sha512.New
+	default:
+		newHash = sha256.New
 	}
 
-	return req, cancel
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write([]byte(canonical))
+	sum := mac.Sum(nil)
+
+	if strings.ToLower(strings.TrimSpace(encoding)) == "base64" {
+		return base64.StdEncoding.EncodeToString(sum), nil
+	}
+	return hex.EncodeToString(sum), nil
 }
 
 func expandPlaceholders(value any) any {
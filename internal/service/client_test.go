@@ -0,0 +1,130 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"net/url"
+	"testing"
+)
+
+func Test_buildCanonicalString(t *testing.T) {
+	type args struct {
+		fields    []string
+		method    string
+		rawURL    string
+		timestamp string
+		bodyJSON  []byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "method, path, query, timestamp, and base64 body in order",
+			args: args{
+				fields:    []string{"method", "path", "query", "timestamp", "body"},
+				method:    "post",
+				rawURL:    "https://api.example.com/orders?b=2&a=1",
+				timestamp: "1690000000000",
+				bodyJSON:  []byte(`{"x":1}`),
+			},
+			want: "POST" + "/orders" + "a=1&b=2" + "1690000000000" + base64.StdEncoding.EncodeToString([]byte(`{"x":1}`)),
+		},
+		{
+			name: "empty body still base64-encodes to an empty string",
+			args: args{
+				fields:    []string{"body"},
+				method:    "GET",
+				rawURL:    "https://api.example.com/",
+				timestamp: "1",
+				bodyJSON:  nil,
+			},
+			want: "",
+		},
+		{
+			name: "unknown field is rejected",
+			args: args{
+				fields: []string{"nope"},
+				method: "GET",
+				rawURL: "https://api.example.com/",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := url.Parse(tt.args.rawURL)
+			if err != nil {
+				t.Fatalf("parse test url: %v", err)
+			}
+
+			got, err := buildCanonicalString(tt.args.fields, tt.args.method, parsed, tt.args.timestamp, tt.args.bodyJSON)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildCanonicalString() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("buildCanonicalString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func expectedHMAC(newHash func() hash.Hash, secret, canonical, encoding string) string {
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write([]byte(canonical))
+	sum := mac.Sum(nil)
+	if encoding == "base64" {
+		return base64.StdEncoding.EncodeToString(sum)
+	}
+	return hex.EncodeToString(sum)
+}
+
+func Test_signHMAC(t *testing.T) {
+	type args struct {
+		algorithm string
+		secret    string
+		canonical string
+		encoding  string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "default algorithm is hmac-sha256, hex encoded",
+			args: args{algorithm: "", secret: "s3cr3t", canonical: "payload"},
+			want: expectedHMAC(sha256.New, "s3cr3t", "payload", ""),
+		},
+		{
+			name: "hmac-sha512 with base64 encoding",
+			args: args{algorithm: "HMAC-SHA512", secret: "s3cr3t", canonical: "payload", encoding: "base64"},
+			want: expectedHMAC(sha512.New, "s3cr3t", "payload", "base64"),
+		},
+		{
+			name: "unrecognized algorithm falls back to hmac-sha256",
+			args: args{algorithm: "hmac-md5", secret: "s3cr3t", canonical: "payload"},
+			want: expectedHMAC(sha256.New, "s3cr3t", "payload", ""),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := signHMAC(tt.args.algorithm, tt.args.secret, tt.args.canonical, tt.args.encoding)
+			if err != nil {
+				t.Fatalf("signHMAC() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("signHMAC() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
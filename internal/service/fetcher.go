@@ -0,0 +1,14 @@
+package service
+
+import (
+	"context"
+
+	"github.com/sarff/FundsPulse/internal/config"
+)
+
+// Fetcher retrieves balance entries for a single service using one protocol
+// (plain JSON-over-HTTP, GraphQL, HTML scraping, SOAP, ...). Implementations
+// are registered in Registry and selected by config.ServiceConfig.Kind.
+type Fetcher interface {
+	Fetch(ctx context.Context, cfg config.ServiceConfig) ([]BalanceEntry, error)
+}
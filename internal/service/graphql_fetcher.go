@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sarff/FundsPulse/internal/config"
+)
+
+// GraphQLFetcher posts a GraphQL query + variables and extracts balance
+// values from the response the same way JSONFetcher does.
+type GraphQLFetcher struct {
+	client *Client
+}
+
+// NewGraphQLFetcher builds a GraphQL fetcher on top of client.
+func NewGraphQLFetcher(client *Client) *GraphQLFetcher {
+	return &GraphQLFetcher{client: client}
+}
+
+// Fetch posts cfg.GraphQL.Query/Variables and extracts cfg.Response.BalancePath.
+func (f *GraphQLFetcher) Fetch(ctx context.Context, cfg config.ServiceConfig) ([]BalanceEntry, error) {
+	if cfg.GraphQL == nil {
+		return nil, fmt.Errorf("service %q: graphql config is required", cfg.Name)
+	}
+
+	authToken, err := f.client.resolveAuthToken(ctx, cfg.Name, cfg.Auth)
+	if err != nil {
+		return nil, err
+	}
+
+	req, cancel, err := f.client.prepareRequest(ctx, cfg.GraphQL.Request)
+	if cancel != nil {
+		defer cancel()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("graphql request %s: %v", cfg.Name, err)
+	}
+	applyAuthHeader(req, cfg.Auth, authToken)
+
+	body := map[string]any{
+		"query":     cfg.GraphQL.Query,
+		"variables": expandPlaceholders(cfg.GraphQL.Variables),
+	}
+	req.SetBody(body)
+
+	requestURL := os.ExpandEnv(cfg.GraphQL.Request.URL)
+	if err := applySignedAuth(req, "POST", requestURL, body, cfg.Auth); err != nil {
+		return nil, fmt.Errorf("graphql request %s: %v", cfg.Name, err)
+	}
+
+	resp, err := req.Execute("POST", requestURL)
+	if err != nil {
+		return nil, fmt.Errorf("graphql request %s: %v", cfg.Name, err)
+	}
+
+	if !resp.IsSuccess() {
+		return nil, fmt.Errorf("graphql request %s: unexpected status %d", cfg.Name, resp.StatusCode())
+	}
+
+	return extractEntries(cfg.Name, resp.Body(), cfg.Response)
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/sarff/FundsPulse/internal/config"
+)
+
+// HTMLFetcher fetches a web page and pulls the balance out of it with a CSS
+// selector, optionally post-processed with a regular expression.
+type HTMLFetcher struct {
+	client *Client
+}
+
+// NewHTMLFetcher builds an HTML fetcher on top of client.
+func NewHTMLFetcher(client *Client) *HTMLFetcher {
+	return &HTMLFetcher{client: client}
+}
+
+// Fetch requests cfg.HTML.Request.URL, selects cfg.HTML.Selector, and parses
+// the resulting text (optionally filtered by cfg.HTML.Regex) as a balance.
+func (f *HTMLFetcher) Fetch(ctx context.Context, cfg config.ServiceConfig) ([]BalanceEntry, error) {
+	if cfg.HTML == nil {
+		return nil, fmt.Errorf("service %q: html config is required", cfg.Name)
+	}
+
+	authToken, err := f.client.resolveAuthToken(ctx, cfg.Name, cfg.Auth)
+	if err != nil {
+		return nil, err
+	}
+
+	req, cancel, err := f.client.prepareRequest(ctx, cfg.HTML.Request)
+	if cancel != nil {
+		defer cancel()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("html request %s: %v", cfg.Name, err)
+	}
+	applyAuthHeader(req, cfg.Auth, authToken)
+
+	method := strings.ToUpper(strings.TrimSpace(cfg.HTML.Request.Method))
+	if method == "" {
+		method = "GET"
+	}
+
+	requestURL := os.ExpandEnv(cfg.HTML.Request.URL)
+	if err := applySignedAuth(req, method, requestURL, expandPlaceholders(cfg.HTML.Request.Body), cfg.Auth); err != nil {
+		return nil, fmt.Errorf("html request %s: %v", cfg.Name, err)
+	}
+
+	resp, err := req.Execute(method, requestURL)
+	if err != nil {
+		return nil, fmt.Errorf("html request %s: %v", cfg.Name, err)
+	}
+
+	if !resp.IsSuccess() {
+		return nil, fmt.Errorf("html request %s: unexpected status %d", cfg.Name, resp.StatusCode())
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(resp.Body()))
+	if err != nil {
+		return nil, fmt.Errorf("html request %s: parse page: %v", cfg.Name, err)
+	}
+
+	selection := doc.Find(cfg.HTML.Selector)
+	if selection.Length() == 0 {
+		return nil, fmt.Errorf("html request %s: selector %q matched nothing", cfg.Name, cfg.HTML.Selector)
+	}
+
+	text := strings.TrimSpace(selection.First().Text())
+	if cfg.HTML.Regex != "" {
+		re, err := regexp.Compile(cfg.HTML.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("html request %s: compile regex: %v", cfg.Name, err)
+		}
+		matches := re.FindStringSubmatch(text)
+		if len(matches) < 2 {
+			return nil, fmt.Errorf("html request %s: regex %q did not match %q", cfg.Name, cfg.HTML.Regex, text)
+		}
+		text = matches[1]
+	}
+
+	amount, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+	if err != nil {
+		return nil, fmt.Errorf("html request %s: parse balance %q: %v", cfg.Name, text, err)
+	}
+
+	scale := cfg.Response.BalanceScale
+	if scale == 0 {
+		scale = 1
+	}
+
+	return []BalanceEntry{{Amount: amount * scale, Currency: cfg.CurrencySymbol}}, nil
+}
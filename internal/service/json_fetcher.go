@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sarff/gjson"
+
+	"github.com/sarff/FundsPulse/internal/config"
+)
+
+// JSONFetcher requests a JSON-over-HTTP endpoint and extracts balance values
+// via cfg.Response.BalancePath. This is the original, and default, protocol.
+type JSONFetcher struct {
+	client *Client
+}
+
+// NewJSONFetcher builds a JSON fetcher on top of client.
+func NewJSONFetcher(client *Client) *JSONFetcher {
+	return &JSONFetcher{client: client}
+}
+
+// Fetch requests service balance entries and optional currency values.
+func (f *JSONFetcher) Fetch(ctx context.Context, cfg config.ServiceConfig) ([]BalanceEntry, error) {
+	authToken, err := f.client.resolveAuthToken(ctx, cfg.Name, cfg.Auth)
+	if err != nil {
+		return nil, err
+	}
+
+	req, cancel, err := f.client.prepareRequest(ctx, cfg.Request)
+	if cancel != nil {
+		defer cancel()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("request %s: %v", cfg.Name, err)
+	}
+	applyAuthHeader(req, cfg.Auth, authToken)
+
+	method := strings.ToUpper(strings.TrimSpace(cfg.Request.Method))
+	if method == "" {
+		method = "GET"
+	}
+
+	requestURL := os.ExpandEnv(cfg.Request.URL)
+	if err := applySignedAuth(req, method, requestURL, expandPlaceholders(cfg.Request.Body), cfg.Auth); err != nil {
+		return nil, fmt.Errorf("request %s: %v", cfg.Name, err)
+	}
+
+	resp, err := req.Execute(method, requestURL)
+	if err != nil {
+		return nil, fmt.Errorf("request %s: %v", cfg.Name, err)
+	}
+
+	if !resp.IsSuccess() {
+		return nil, fmt.Errorf("request %s: unexpected status %d", cfg.Name, resp.StatusCode())
+	}
+
+	return extractEntries(cfg.Name, resp.Body(), cfg.Response)
+}
+
+// extractEntries pulls balance (and optional currency) values out of a JSON
+// payload using cfg.Response, shared by every fetcher that returns JSON.
+func extractEntries(name string, payload []byte, cfg config.ResponseConfig) ([]BalanceEntry, error) {
+	balanceValue := gjson.GetBytes(payload, cfg.BalancePath)
+	if !balanceValue.Exists() {
+		return nil, fmt.Errorf("request %s: balance path %q not found", name, cfg.BalancePath)
+	}
+
+	scale := cfg.BalanceScale
+	if scale == 0 {
+		scale = 1
+	}
+
+	var entries []BalanceEntry
+	if cfg.Multiple {
+		if !balanceValue.IsArray() {
+			return nil, fmt.Errorf("request %s: balance path %q is not an array", name, cfg.BalancePath)
+		}
+
+		values := balanceValue.Array()
+		entries = make([]BalanceEntry, 0, len(values))
+		for _, item := range values {
+			entries = append(entries, BalanceEntry{Amount: item.Float() * scale})
+		}
+	} else {
+		entries = []BalanceEntry{{Amount: balanceValue.Float() * scale}}
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("request %s: no balances found", name)
+	}
+
+	if cfg.CurrencyField != "" {
+		currencyValue := gjson.GetBytes(payload, cfg.CurrencyField)
+		if currencyValue.Exists() {
+			if cfg.Multiple && currencyValue.IsArray() {
+				currencies := currencyValue.Array()
+				for i := range entries {
+					if i < len(currencies) {
+						entries[i].Currency = strings.TrimSpace(currencies[i].String())
+					}
+				}
+			} else {
+				currency := strings.TrimSpace(currencyValue.String())
+				for i := range entries {
+					entries[i].Currency = currency
+				}
+			}
+		}
+	}
+
+	return entries, nil
+}
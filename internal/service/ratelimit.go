@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// hostLimiter rate-limits outgoing requests per destination host with a
+// token-bucket limiter, so a burst of concurrently-fetched services pointed
+// at the same API doesn't hammer it even when plenty of worker slots are
+// free. A non-positive rps disables limiting entirely.
+type hostLimiter struct {
+	rps   float64
+	burst int
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+func newHostLimiter(rps float64, burst int) *hostLimiter {
+	return &hostLimiter{rps: rps, burst: burst, limiters: make(map[string]*rate.Limiter)}
+}
+
+// wait blocks until rawURL's host is permitted another request, or ctx is
+// done. It is a no-op when limiting is disabled or rawURL has no host.
+func (h *hostLimiter) wait(ctx context.Context, rawURL string) error {
+	if h.rps <= 0 {
+		return nil
+	}
+
+	host := hostOf(rawURL)
+	if host == "" {
+		return nil
+	}
+
+	return h.limiterFor(host).Wait(ctx)
+}
+
+func (h *hostLimiter) limiterFor(host string) *rate.Limiter {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	limiter, ok := h.limiters[host]
+	if !ok {
+		burst := h.burst
+		if burst < 1 {
+			burst = 1
+		}
+		limiter = rate.NewLimiter(rate.Limit(h.rps), burst)
+		h.limiters[host] = limiter
+	}
+	return limiter
+}
+
+func hostOf(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Host
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sarff/FundsPulse/internal/config"
+	"github.com/sarff/FundsPulse/internal/fx"
+)
+
+// Registry dispatches FetchBalance-style calls to the Fetcher matching a
+// service's configured kind, so new protocols can be added without touching
+// the scheduler. When rates is set, every entry is additionally normalized
+// into reportingCurrency.
+type Registry struct {
+	fetchers          map[string]Fetcher
+	rates             fx.Rates
+	reportingCurrency string
+}
+
+// NewRegistry builds the default registry wired to client's HTTP mechanics.
+// rates may be nil to skip currency normalization entirely.
+func NewRegistry(client *Client, rates fx.Rates, reportingCurrency string) *Registry {
+	return &Registry{
+		fetchers: map[string]Fetcher{
+			"json":    NewJSONFetcher(client),
+			"graphql": NewGraphQLFetcher(client),
+			"html":    NewHTMLFetcher(client),
+			"soap":    NewSOAPFetcher(client),
+		},
+		rates:             rates,
+		reportingCurrency: reportingCurrency,
+	}
+}
+
+// Fetch resolves cfg.Kind (defaulting to "json"), dispatches to its Fetcher,
+// and normalizes each entry into the reporting currency when configured.
+func (r *Registry) Fetch(ctx context.Context, cfg config.ServiceConfig) ([]BalanceEntry, error) {
+	kind := cfg.Kind
+	if kind == "" {
+		kind = "json"
+	}
+
+	fetcher, ok := r.fetchers[kind]
+	if !ok {
+		return nil, fmt.Errorf("service %q: unknown fetcher kind %q", cfg.Name, kind)
+	}
+
+	entries, err := fetcher.Fetch(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range entries {
+		r.normalize(ctx, &entries[i])
+	}
+	return entries, nil
+}
+
+// normalize records entry's as-fetched amount/currency as Native*, then
+// converts Amount/Currency into r.reportingCurrency when rates is
+// configured. A failed or unconfigured conversion leaves the entry in its
+// native currency, so a transient FX outage degrades reporting rather than
+// halting it.
+func (r *Registry) normalize(ctx context.Context, entry *BalanceEntry) {
+	entry.NativeAmount = entry.Amount
+	entry.NativeCurrency = entry.Currency
+
+	currency := strings.ToUpper(strings.TrimSpace(entry.Currency))
+	if r.rates == nil || r.reportingCurrency == "" || currency == "" || currency == strings.ToUpper(r.reportingCurrency) {
+		return
+	}
+
+	rate, err := r.rates.Rate(ctx, currency, r.reportingCurrency, time.Now())
+	if err != nil {
+		return
+	}
+
+	entry.Amount *= rate
+	entry.Currency = r.reportingCurrency
+}
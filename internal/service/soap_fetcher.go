@@ -0,0 +1,104 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/antchfx/xmlquery"
+
+	"github.com/sarff/FundsPulse/internal/config"
+)
+
+// SOAPFetcher posts a templated XML envelope and extracts the balance with
+// an XPath expression against the response body.
+type SOAPFetcher struct {
+	client *Client
+}
+
+// NewSOAPFetcher builds a SOAP fetcher on top of client.
+func NewSOAPFetcher(client *Client) *SOAPFetcher {
+	return &SOAPFetcher{client: client}
+}
+
+// Fetch renders cfg.SOAP.BodyTemplate, posts it, and resolves cfg.SOAP.XPath
+// against the returned XML document.
+func (f *SOAPFetcher) Fetch(ctx context.Context, cfg config.ServiceConfig) ([]BalanceEntry, error) {
+	if cfg.SOAP == nil {
+		return nil, fmt.Errorf("service %q: soap config is required", cfg.Name)
+	}
+
+	authToken, err := f.client.resolveAuthToken(ctx, cfg.Name, cfg.Auth)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := renderSOAPBody(cfg.SOAP.BodyTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("soap request %s: %v", cfg.Name, err)
+	}
+
+	req, cancel, err := f.client.prepareRequest(ctx, cfg.SOAP.Request)
+	if cancel != nil {
+		defer cancel()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("soap request %s: %v", cfg.Name, err)
+	}
+	applyAuthHeader(req, cfg.Auth, authToken)
+	req.SetHeader("Content-Type", "text/xml; charset=utf-8")
+	req.SetBody(body)
+
+	requestURL := os.ExpandEnv(cfg.SOAP.Request.URL)
+	if err := applySignedAuth(req, "POST", requestURL, body, cfg.Auth); err != nil {
+		return nil, fmt.Errorf("soap request %s: %v", cfg.Name, err)
+	}
+
+	resp, err := req.Execute("POST", requestURL)
+	if err != nil {
+		return nil, fmt.Errorf("soap request %s: %v", cfg.Name, err)
+	}
+
+	if !resp.IsSuccess() {
+		return nil, fmt.Errorf("soap request %s: unexpected status %d", cfg.Name, resp.StatusCode())
+	}
+
+	doc, err := xmlquery.Parse(bytes.NewReader(resp.Body()))
+	if err != nil {
+		return nil, fmt.Errorf("soap request %s: parse response: %v", cfg.Name, err)
+	}
+
+	node := xmlquery.FindOne(doc, cfg.SOAP.XPath)
+	if node == nil {
+		return nil, fmt.Errorf("soap request %s: xpath %q matched nothing", cfg.Name, cfg.SOAP.XPath)
+	}
+
+	amount, err := strconv.ParseFloat(strings.TrimSpace(node.InnerText()), 64)
+	if err != nil {
+		return nil, fmt.Errorf("soap request %s: parse balance: %v", cfg.Name, err)
+	}
+
+	scale := cfg.Response.BalanceScale
+	if scale == 0 {
+		scale = 1
+	}
+
+	return []BalanceEntry{{Amount: amount * scale, Currency: cfg.CurrencySymbol}}, nil
+}
+
+func renderSOAPBody(bodyTemplate string) (string, error) {
+	tmpl, err := template.New("soap").Parse(bodyTemplate)
+	if err != nil {
+		return "", fmt.Errorf("parse body_template: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, map[string]string{}); err != nil {
+		return "", fmt.Errorf("render body_template: %v", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,112 @@
+package webserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sarff/gjson"
+
+	"github.com/sarff/FundsPulse/internal/config"
+)
+
+// Problem describes a single configuration issue surfaced to the dashboard.
+type Problem struct {
+	Service  string `json:"service,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// CheckProblems re-runs config.Load style validation against the already
+// parsed configuration and adds live probes on top: auth URL reachability,
+// JSON path resolution against a sample response, and timezone parsing.
+// It never mutates cfg and never fails the caller; every issue found is
+// reported as a Problem instead.
+func CheckProblems(ctx context.Context, cfg *config.Config, probeClient *http.Client) []Problem {
+	var problems []Problem
+
+	if _, err := time.LoadLocation(cfg.Schedule.Timezone); cfg.Schedule.Timezone != "" && err != nil {
+		problems = append(problems, Problem{Severity: "error", Message: fmt.Sprintf("schedule.timezone: %v", err)})
+	}
+	if _, err := time.Parse("15:04", cfg.Schedule.Time); err != nil {
+		problems = append(problems, Problem{Severity: "error", Message: fmt.Sprintf("schedule.time: %v", err)})
+	}
+
+	if probeClient == nil {
+		probeClient = &http.Client{Timeout: 5 * time.Second}
+	}
+
+	for _, svc := range cfg.Services {
+		problems = append(problems, probeService(ctx, probeClient, svc)...)
+	}
+
+	return problems
+}
+
+func probeService(ctx context.Context, client *http.Client, svc config.ServiceConfig) []Problem {
+	var problems []Problem
+
+	if svc.Auth != nil {
+		if err := probeURL(ctx, client, svc.Auth.Request.URL); err != nil {
+			problems = append(problems, Problem{
+				Service:  svc.Name,
+				Severity: "warning",
+				Message:  fmt.Sprintf("auth.request.url unreachable: %v", err),
+			})
+		}
+	}
+
+	if err := probeURL(ctx, client, svc.Request.URL); err != nil {
+		problems = append(problems, Problem{
+			Service:  svc.Name,
+			Severity: "warning",
+			Message:  fmt.Sprintf("request.url unreachable: %v", err),
+		})
+	}
+
+	if sample := strings.TrimSpace(svc.Response.SampleResponse); sample != "" {
+		if err := CheckSamplePath([]byte(sample), svc.Response.BalancePath); err != nil {
+			problems = append(problems, Problem{
+				Service:  svc.Name,
+				Severity: "error",
+				Message:  fmt.Sprintf("response.sample_response: %v", err),
+			})
+		}
+	}
+
+	return problems
+}
+
+func probeURL(ctx context.Context, client *http.Client, rawURL string) error {
+	url := os.ExpandEnv(rawURL)
+	if strings.TrimSpace(url) == "" {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return fmt.Errorf("build probe request: %v", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// CheckSamplePath reports whether balancePath resolves against a sample JSON
+// response, letting operators validate a response.balance_path before it
+// ever runs against the live API.
+func CheckSamplePath(sample []byte, balancePath string) error {
+	value := gjson.GetBytes(sample, balancePath)
+	if !value.Exists() {
+		return fmt.Errorf("balance path %q not found in sample response", balancePath)
+	}
+	return nil
+}
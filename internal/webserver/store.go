@@ -0,0 +1,104 @@
+package webserver
+
+import (
+	"sync"
+	"time"
+)
+
+// ServiceSnapshot captures the last known state of a regular service check.
+type ServiceSnapshot struct {
+	Name      string    `json:"name"`
+	Message   string    `json:"message"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// StaticSnapshot captures the last reminder evaluation for a static service.
+type StaticSnapshot struct {
+	Name      string    `json:"name"`
+	Message   string    `json:"message"`
+	Fired     bool      `json:"fired"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Snapshot is the full state exposed by the dashboard.
+type Snapshot struct {
+	Services       []ServiceSnapshot `json:"services"`
+	StaticServices []StaticSnapshot  `json:"static_services"`
+	LastRun        time.Time         `json:"last_run"`
+	LastError      string            `json:"last_error,omitempty"`
+}
+
+// Store keeps the most recent check results in memory so the dashboard
+// always reflects the last tick without hitting remote services again.
+type Store struct {
+	mu             sync.RWMutex
+	services       map[string]ServiceSnapshot
+	staticServices map[string]StaticSnapshot
+	lastRun        time.Time
+	lastErr        string
+}
+
+// NewStore builds an empty in-memory store.
+func NewStore() *Store {
+	return &Store{
+		services:       make(map[string]ServiceSnapshot),
+		staticServices: make(map[string]StaticSnapshot),
+	}
+}
+
+// RecordService stores the outcome of a single service check.
+func (s *Store) RecordService(name, message string, err error) {
+	snap := ServiceSnapshot{Name: name, Message: message, CheckedAt: time.Now()}
+	if err != nil {
+		snap.Error = err.Error()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.services[name] = snap
+}
+
+// RecordStatic stores the outcome of a static service reminder evaluation.
+func (s *Store) RecordStatic(name, message string, fired bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.staticServices[name] = StaticSnapshot{
+		Name:      name,
+		Message:   message,
+		Fired:     fired,
+		CheckedAt: time.Now(),
+	}
+}
+
+// RecordRun marks a completed RunOnce cycle, along with its first error, if any.
+func (s *Store) RecordRun(runAt time.Time, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastRun = runAt
+	if err != nil {
+		s.lastErr = err.Error()
+	} else {
+		s.lastErr = ""
+	}
+}
+
+// Snapshot returns a consistent copy of the current state.
+func (s *Store) Snapshot() Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := Snapshot{
+		Services:       make([]ServiceSnapshot, 0, len(s.services)),
+		StaticServices: make([]StaticSnapshot, 0, len(s.staticServices)),
+		LastRun:        s.lastRun,
+		LastError:      s.lastErr,
+	}
+	for _, snap := range s.services {
+		out.Services = append(out.Services, snap)
+	}
+	for _, snap := range s.staticServices {
+		out.StaticServices = append(out.StaticServices, snap)
+	}
+	return out
+}
@@ -0,0 +1,172 @@
+// Package webserver exposes the dashboard HTTP UI and JSON API backed by
+// the in-memory Store that the checker populates on every RunOnce.
+package webserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sarff/iSlogger"
+
+	"github.com/sarff/FundsPulse/internal/config"
+	"github.com/sarff/FundsPulse/internal/history"
+)
+
+// Server serves the dashboard UI and its JSON API.
+type Server struct {
+	cfg     *config.Config
+	store   *Store
+	history *history.Manager
+	logger  *iSlogger.Logger
+	http    *http.Server
+}
+
+// NewServer builds a dashboard server bound to addr (e.g. ":8080").
+// historyManager backs the per-service history endpoint; it may be nil, in
+// which case that endpoint always reports 503.
+func NewServer(addr string, cfg *config.Config, store *Store, historyManager *history.Manager, logger *iSlogger.Logger) *Server {
+	s := &Server{cfg: cfg, store: store, history: historyManager, logger: logger}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleIndex)
+	mux.HandleFunc("/api/snapshot", s.handleSnapshot)
+	mux.HandleFunc("/api/problems", s.handleProblems)
+	mux.HandleFunc("/api/services/{name}/history", s.handleServiceHistory)
+
+	s.http = &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return s
+}
+
+// Start runs the HTTP server until ctx is cancelled.
+func (s *Server) Start(ctx context.Context) error {
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := s.http.Shutdown(shutdownCtx); err != nil {
+			s.logger.Error("shutdown webserver", "error", err)
+		}
+	}()
+
+	s.logger.Info("Dashboard listening", "addr", s.http.Addr)
+	if err := s.http.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) handleSnapshot(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.store.Snapshot())
+}
+
+func (s *Server) handleProblems(w http.ResponseWriter, r *http.Request) {
+	problems := CheckProblems(r.Context(), s.cfg, nil)
+	writeJSON(w, problems)
+}
+
+// handleServiceHistory serves the daily spend history (capped to
+// cfg.DaysForAverage, or to a "days" query param override) backing the
+// dashboard's per-service history view.
+func (s *Server) handleServiceHistory(w http.ResponseWriter, r *http.Request) {
+	if s.history == nil {
+		http.Error(w, "history is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	svc, ok := s.findService(r.PathValue("name"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if svc.Response.Multiple {
+		http.Error(w, fmt.Sprintf("service %q: multi-entry services are not yet supported by this endpoint", svc.Name), http.StatusNotImplemented)
+		return
+	}
+
+	days := s.cfg.DaysForAverage
+	if raw := r.URL.Query().Get("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "days must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		days = parsed
+	}
+
+	record, err := s.history.Load(svc.HistoryFile)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	spends := record.DailySpends
+	if len(spends) > days {
+		spends = spends[len(spends)-days:]
+	}
+
+	writeJSON(w, struct {
+		Service string               `json:"service"`
+		Days    int                  `json:"days"`
+		Spends  []history.DailySpend `json:"spends"`
+	}{Service: svc.Name, Days: days, Spends: spends})
+}
+
+// findService looks up a configured service by name.
+func (s *Server) findService(name string) (config.ServiceConfig, bool) {
+	for _, svc := range s.cfg.Services {
+		if svc.Name == name {
+			return svc, true
+		}
+	}
+	return config.ServiceConfig{}, false
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write([]byte(dashboardHTML))
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+const dashboardHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>FundsPulse</title>
+</head>
+<body>
+  <h1>FundsPulse</h1>
+  <section id="balances"></section>
+  <h2>Configuration Problems</h2>
+  <section id="problems"></section>
+  <script>
+    async function refresh() {
+      const snapshot = await fetch('/api/snapshot').then(r => r.json());
+      document.getElementById('balances').innerText = JSON.stringify(snapshot, null, 2);
+      const problems = await fetch('/api/problems').then(r => r.json());
+      document.getElementById('problems').innerText = JSON.stringify(problems, null, 2);
+    }
+    refresh();
+    setInterval(refresh, 30000);
+  </script>
+</body>
+</html>
+`